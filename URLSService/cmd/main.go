@@ -9,18 +9,23 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
 
 	api_middleware "github.com/ArtemBorodinEvgenyevich/URLSService/internal/api/middleware"
 	apiv1 "github.com/ArtemBorodinEvgenyevich/URLSService/internal/api/v1"
+	apiweb "github.com/ArtemBorodinEvgenyevich/URLSService/internal/api/web"
 	cache_redis "github.com/ArtemBorodinEvgenyevich/URLSService/internal/cache/redis"
 	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/config"
 	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	ratelimit_redis "github.com/ArtemBorodinEvgenyevich/URLSService/internal/ratelimit/redis"
 	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+	file_repo "github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository/file"
 	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository/postgres"
 	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/service"
+	stream_redis "github.com/ArtemBorodinEvgenyevich/URLSService/internal/stream/redis"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/joho/godotenv"
@@ -76,6 +81,123 @@ func main() {
 		return
 	}
 
+	// Load redirect configuration from environment
+	logger.AppLogInfo("Loading redirect configuration")
+	redirectConfig, err := config.LoadRedirectConfigFromEnv()
+	if err != nil {
+		logger.AppLogError("Failed to load redirect configuration", zap.Error(err))
+		exitCode = 1
+		return
+	}
+
+	// Load click stream configuration from environment
+	logger.AppLogInfo("Loading click stream configuration")
+	clickStreamConfig, err := config.LoadClickStreamConfigFromEnv()
+	if err != nil {
+		logger.AppLogError("Failed to load click stream configuration", zap.Error(err))
+		exitCode = 1
+		return
+	}
+
+	// Load URL validation configuration from environment
+	logger.AppLogInfo("Loading URL validation configuration")
+	urlValidationConfig, err := config.LoadURLValidationConfigFromEnv()
+	if err != nil {
+		logger.AppLogError("Failed to load URL validation configuration", zap.Error(err))
+		exitCode = 1
+		return
+	}
+
+	// Load blocklist configuration from environment
+	logger.AppLogInfo("Loading blocklist configuration")
+	blocklistConfig, err := config.LoadBlocklistConfigFromEnv()
+	if err != nil {
+		logger.AppLogError("Failed to load blocklist configuration", zap.Error(err))
+		exitCode = 1
+		return
+	}
+
+	// Load link password configuration from environment
+	logger.AppLogInfo("Loading link password configuration")
+	linkPasswordConfig, err := config.LoadLinkPasswordConfigFromEnv()
+	if err != nil {
+		logger.AppLogError("Failed to load link password configuration", zap.Error(err))
+		exitCode = 1
+		return
+	}
+
+	// Load expired URL purge configuration from environment
+	logger.AppLogInfo("Loading URL purge configuration")
+	purgeConfig, err := config.LoadPurgeConfigFromEnv()
+	if err != nil {
+		logger.AppLogError("Failed to load URL purge configuration", zap.Error(err))
+		exitCode = 1
+		return
+	}
+
+	// Load idempotency configuration from environment
+	logger.AppLogInfo("Loading idempotency configuration")
+	idempotencyConfig, err := config.LoadIdempotencyConfigFromEnv()
+	if err != nil {
+		logger.AppLogError("Failed to load idempotency configuration", zap.Error(err))
+		exitCode = 1
+		return
+	}
+
+	// Load short code configuration from environment
+	logger.AppLogInfo("Loading short code configuration")
+	shortCodeConfig, err := config.LoadShortCodeConfigFromEnv()
+	if err != nil {
+		logger.AppLogError("Failed to load short code configuration", zap.Error(err))
+		exitCode = 1
+		return
+	}
+
+	// Load short code pool configuration from environment
+	logger.AppLogInfo("Loading short code pool configuration")
+	codePoolConfig, err := config.LoadCodePoolConfigFromEnv()
+	if err != nil {
+		logger.AppLogError("Failed to load short code pool configuration", zap.Error(err))
+		exitCode = 1
+		return
+	}
+
+	// Load QR code configuration from environment
+	logger.AppLogInfo("Loading QR code configuration")
+	qrConfig, err := config.LoadQRConfigFromEnv()
+	if err != nil {
+		logger.AppLogError("Failed to load QR code configuration", zap.Error(err))
+		exitCode = 1
+		return
+	}
+
+	// Load destination metadata configuration from environment
+	logger.AppLogInfo("Loading metadata configuration")
+	metadataConfig, err := config.LoadMetadataConfigFromEnv()
+	if err != nil {
+		logger.AppLogError("Failed to load metadata configuration", zap.Error(err))
+		exitCode = 1
+		return
+	}
+
+	// Load link check configuration from environment
+	logger.AppLogInfo("Loading link check configuration")
+	linkCheckConfig, err := config.LoadLinkCheckConfigFromEnv()
+	if err != nil {
+		logger.AppLogError("Failed to load link check configuration", zap.Error(err))
+		exitCode = 1
+		return
+	}
+
+	// Load geoip configuration from environment
+	logger.AppLogInfo("Loading geoip configuration")
+	geoIPConfig, err := config.LoadGeoIPConfigFromEnv()
+	if err != nil {
+		logger.AppLogError("Failed to load geoip configuration", zap.Error(err))
+		exitCode = 1
+		return
+	}
+
 	// Setup signal context - cancels on sigterm or sigint
 	rootCtx, stop := signal.NotifyContext(
 		context.Background(),
@@ -126,9 +248,259 @@ func main() {
 	// Setup DI containers
 	postgresRepo := postgres.NewURLRepository(pool, dbConfig.QueryTimeout())
 
+	clickRepo := postgres.NewClickRepository(pool, dbConfig.QueryTimeout())
+	labelRepo := postgres.NewLabelRepository(pool, dbConfig.QueryTimeout())
+	healthRepo := postgres.NewHealthRepository(pool, dbConfig.QueryTimeout())
+
 	urlCache := cache_redis.NewURLCache(redisClient)
 	urlRepo := repository.NewCachingRepository(postgresRepo, urlCache)
-	urlService := service.NewURLService(urlRepo)
+	clickStream := stream_redis.NewClickStream(
+		redisClient,
+		clickStreamConfig.Name(),
+		clickStreamConfig.Group(),
+		clickStreamConfig.Consumer(),
+		clickStreamConfig.MaxLen(),
+	)
+	clickRecorder := service.NewStreamClickRecorder(clickStream, clickStreamConfig.PublishTimeout())
+	clickConsumer := service.NewClickConsumer(
+		clickStream,
+		clickRepo,
+		clickStreamConfig.BatchSize(),
+		clickStreamConfig.BlockTimeout(),
+		clickStreamConfig.ClaimIdle(),
+		clickStreamConfig.ClaimInterval(),
+	)
+	urlValidator := service.NewURLValidator(service.URLValidatorOptions{
+		MaxLength:         urlValidationConfig.MaxLength(),
+		StripFragment:     urlValidationConfig.StripFragment(),
+		AllowPrivateHosts: urlValidationConfig.AllowPrivateHosts(),
+	})
+
+	var blocklistRepo repository.BlocklistRepository
+	switch blocklistConfig.Source() {
+	case config.BlocklistSourceFile:
+		blocklistRepo = file_repo.NewBlocklistRepository(blocklistConfig.FilePath())
+	case config.BlocklistSourcePostgres:
+		blocklistRepo = postgres.NewBlocklistRepository(pool, dbConfig.QueryTimeout())
+	}
+	blocklist := service.NewBlocklist(blocklistRepo, blocklistConfig.ReloadInterval())
+	if err := blocklist.Reload(context.Background()); err != nil {
+		logger.AppLogError("Unable to load blocklist", zap.Error(err))
+		exitCode = 1
+		return
+	}
+
+	passwordLimiter := ratelimit_redis.NewAttemptLimiter(
+		redisClient,
+		"pwattempts:",
+		linkPasswordConfig.MaxAttempts(),
+		linkPasswordConfig.AttemptWindow(),
+	)
+	linkPasswords := service.NewLinkPasswords(passwordLimiter, linkPasswordConfig.BcryptCost())
+
+	clickCounter := cache_redis.NewClickCounter(redisClient)
+
+	codeAlphabet := service.AlphabetBase62
+	if shortCodeConfig.Unambiguous() {
+		codeAlphabet = service.AlphabetUnambiguous
+	}
+
+	var codeGenerator service.CodeGenerator
+	switch shortCodeConfig.Strategy() {
+	case config.ShortCodeStrategySequence:
+		codeGenerator = service.NewSequenceCodeGenerator(
+			postgres.NewCodeSequence(pool, dbConfig.QueryTimeout()),
+			codeAlphabet,
+			shortCodeConfig.SequenceKey(),
+		)
+	case config.ShortCodeStrategyWordlist:
+		codeGenerator = service.NewWordlistCodeGenerator(shortCodeConfig.Words(), service.CodeGrowth{
+			Window:    shortCodeConfig.GrowthWindow(),
+			Threshold: shortCodeConfig.GrowthThreshold(),
+			Max:       config.MaxShortCodeWords,
+		})
+	default:
+		codeGenerator = service.NewRandomCodeGenerator(codeAlphabet, shortCodeConfig.Length(), service.CodeGrowth{
+			Window:    shortCodeConfig.GrowthWindow(),
+			Threshold: shortCodeConfig.GrowthThreshold(),
+			Max:       config.MaxShortCodeLength,
+		})
+	}
+
+	if shortCodeConfig.DenylistFile() != "" {
+		denylist, err := service.LoadCodeDenylist(shortCodeConfig.DenylistFile())
+		if err != nil {
+			logger.AppLogError("Unable to load short code denylist", zap.Error(err))
+			exitCode = 1
+			return
+		}
+		codeGenerator = service.NewFilteredCodeGenerator(codeGenerator, denylist)
+	}
+
+	var codePool *service.CodePool
+	if codePoolConfig.Enabled() {
+		codePool = service.NewCodePool(
+			cache_redis.NewCodePool(redisClient),
+			postgresRepo,
+			postgres.NewAdvisoryLock(pool, postgres.CodePoolLockKey),
+			codeGenerator,
+			codePoolConfig.Size(),
+			codePoolConfig.LowWatermark(),
+			codePoolConfig.BatchSize(),
+			codePoolConfig.Interval(),
+		)
+		codeGenerator = codePool
+	}
+
+	// Interface stays nil when disabled, service checks it before queueing
+	var metadataQueue service.MetadataQueue
+	var metadataCollector *service.MetadataCollector
+	if metadataConfig.Enabled() {
+		metadataCollector = service.NewMetadataCollector(
+			service.NewMetadataFetcher(service.MetadataFetcherOptions{
+				Timeout:           metadataConfig.Timeout(),
+				MaxBodyBytes:      metadataConfig.MaxBodyBytes(),
+				MaxRedirects:      metadataConfig.MaxRedirects(),
+				UserAgent:         metadataConfig.UserAgent(),
+				AllowPrivateHosts: urlValidationConfig.AllowPrivateHosts(),
+			}),
+			postgres.NewMetadataRepository(pool, dbConfig.QueryTimeout()),
+			postgres.NewAdvisoryLock(pool, postgres.MetadataLockKey),
+			metadataConfig.Workers(),
+			metadataConfig.QueueSize(),
+			metadataConfig.SweepInterval(),
+			metadataConfig.SweepBatchSize(),
+		)
+		metadataQueue = metadataCollector
+	}
+
+	// Interface stays nil without database, geo rules use trusted proxy header only then
+	var countryLookup service.CountryLookup
+	var geoIP *service.GeoIP
+	if geoIPConfig.DatabasePath() != "" {
+		geoIP = service.NewGeoIP(geoIPConfig.DatabasePath(), geoIPConfig.ReloadInterval())
+		if err := geoIP.Reload(); err != nil {
+			logger.AppLogError("Unable to load geoip database", zap.Error(err))
+			exitCode = 1
+			return
+		}
+		countryLookup = geoIP
+	}
+
+	urlService := service.NewURLService(
+		urlRepo,
+		clickRecorder,
+		urlValidator,
+		blocklist,
+		linkPasswords,
+		clickCounter,
+		codeGenerator,
+		shortCodeConfig.CaseInsensitive(),
+		metadataQueue,
+		countryLookup,
+	)
+	analyticsService := service.NewAnalyticsService(urlRepo, clickRepo)
+	labelService := service.NewLabelService(labelRepo)
+	linkHealthService := service.NewLinkHealthService(urlRepo, healthRepo)
+	urlPurger := service.NewURLPurger(
+		postgresRepo,
+		postgres.NewAdvisoryLock(pool, postgres.URLPurgeLockKey),
+		purgeConfig.Interval(),
+		purgeConfig.BatchSize(),
+		purgeConfig.BatchPause(),
+		purgeConfig.Retention(),
+		purgeConfig.TrashRetention(),
+	)
+
+	var linkChecker *service.LinkChecker
+	if linkCheckConfig.Enabled() {
+		// Interface stays nil without webhook, checker only records health then
+		var healthNotifier service.LinkHealthNotifier
+		if linkCheckConfig.WebhookURL() != "" {
+			healthNotifier = service.NewWebhookHealthNotifier(
+				linkCheckConfig.WebhookURL(),
+				linkCheckConfig.WebhookSecret(),
+				linkCheckConfig.Timeout(),
+			)
+		}
+		// Caching repository, so links disabled for failures stop resolving right away
+		linkChecker = service.NewLinkChecker(
+			healthRepo,
+			urlRepo,
+			postgres.NewAdvisoryLock(pool, postgres.LinkCheckLockKey),
+			healthNotifier,
+			service.LinkCheckerOptions{
+				Interval:          linkCheckConfig.Interval(),
+				PollInterval:      linkCheckConfig.PollInterval(),
+				BatchSize:         linkCheckConfig.BatchSize(),
+				Timeout:           linkCheckConfig.Timeout(),
+				MaxRedirects:      linkCheckConfig.MaxRedirects(),
+				Concurrency:       linkCheckConfig.Concurrency(),
+				HostConcurrency:   linkCheckConfig.HostConcurrency(),
+				UserAgent:         linkCheckConfig.UserAgent(),
+				NotifyAfter:       linkCheckConfig.NotifyAfter(),
+				DisableAfter:      linkCheckConfig.DisableAfter(),
+				AllowPrivateHosts: urlValidationConfig.AllowPrivateHosts(),
+			},
+		)
+	}
+
+	// Background workers, stopped after HTTP server so in-flight requests can still enqueue work
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var workers sync.WaitGroup
+
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		clickConsumer.Start(workersCtx)
+	}()
+
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		blocklist.Start(workersCtx)
+	}()
+
+	if purgeConfig.Enabled() {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			urlPurger.Start(workersCtx)
+		}()
+	}
+
+	if codePool != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			codePool.Start(workersCtx)
+		}()
+	}
+
+	if metadataCollector != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			metadataCollector.Start(workersCtx)
+		}()
+	}
+
+	if geoIP != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			geoIP.Start(workersCtx)
+		}()
+	}
+
+	if linkChecker != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			linkChecker.Start(workersCtx)
+		}()
+	}
 
 	// Setup chi router
 	router := chi.NewRouter()
@@ -140,13 +512,32 @@ func main() {
 
 	// Register versioned API routes
 	apiConfig := &apiv1.Config{
-		URLService:   urlService,
-		PgPool:       pool,
-		RedisClient:  redisClient,
-		ShuttingDown: &isShuttingDown,
+		URLService:       urlService,
+		LabelService:     labelService,
+		AnalyticsService: analyticsService,
+		LinkHealth:       linkHealthService,
+		PgPool:           pool,
+		RedisClient:      redisClient,
+		ClickLag:         clickConsumer,
+		ClickDrops:       clickRecorder,
+		ShuttingDown:     &isShuttingDown,
+		Idempotency:      cache_redis.NewIdempotencyStore(redisClient),
+		IdempotencyTTL:   idempotencyConfig.TTL(),
+		IdempotencyLock:  idempotencyConfig.LockTTL(),
+		QR:               qrConfig,
+		Redirect:         redirectConfig,
+		GeoIP:            geoIPConfig,
 	}
 	apiv1.RegisterRoutes(router, apiConfig)
 
+	// Register public short link routes
+	webConfig := &apiweb.Config{
+		URLService: urlService,
+		Redirect:   redirectConfig,
+		GeoIP:      geoIPConfig,
+	}
+	apiweb.RegisterRoutes(router, webConfig)
+
 	// HTTP Server configuration
 	port := os.Getenv("APP_PORT")
 	if port == "" {
@@ -225,5 +616,11 @@ func main() {
 		logger.AppLogInfo("Server stopped gracefully")
 	}
 
+	// stop background workers before connections are closed
+	logger.AppLogInfo("Stopping background workers")
+	stopWorkers()
+	workers.Wait()
+	logger.AppLogInfo("Background workers stopped")
+
 	logger.AppLogInfo("Application shutdown complete")
 }
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE urls ADD COLUMN redirect_type SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE urls ADD CONSTRAINT valid_redirect_type CHECK ( redirect_type IN (0, 301, 302, 307, 308) );
+
+COMMENT ON COLUMN urls.redirect_type IS 'HTTP status used for redirect, 0 means service default';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE urls DROP CONSTRAINT IF EXISTS valid_redirect_type;
+ALTER TABLE urls DROP COLUMN IF EXISTS redirect_type;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE urls ALTER COLUMN short_code TYPE VARCHAR(64);
+
+COMMENT ON COLUMN urls.short_code IS 'Generated code or user chosen alias';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Aliases longer than 16 characters can't be narrowed back, rollback stops before touching the column
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM urls WHERE length(short_code) > 16) THEN
+        RAISE EXCEPTION 'urls.short_code holds codes longer than 16 characters, rename or delete them before rolling back';
+    END IF;
+END
+$$;
+
+ALTER TABLE urls ALTER COLUMN short_code TYPE VARCHAR(16);
+-- +goose StatementEnd
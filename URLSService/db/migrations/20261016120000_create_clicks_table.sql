@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS clicks (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    short_code VARCHAR(64) NOT NULL,
+    clicked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    referrer TEXT,
+    user_agent TEXT,
+    ip_address INET,
+    user_id VARCHAR(64)
+);
+
+CREATE INDEX idx_clicks_short_code_clicked_at ON clicks(short_code, clicked_at);
+
+COMMENT ON TABLE clicks IS 'Short link resolutions used for per-link analytics';
+COMMENT ON COLUMN clicks.ip_address IS 'Anonymized client IP (IPv4 /24, IPv6 /48)';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS clicks;
+-- +goose StatementEnd
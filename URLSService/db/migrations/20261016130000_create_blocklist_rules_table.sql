@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS blocklist_rules (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    kind VARCHAR(16) NOT NULL,
+    pattern TEXT NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT valid_blocklist_kind CHECK ( kind IN ('domain', 'wildcard', 'regex') ),
+    CONSTRAINT blocklist_pattern_not_empty CHECK ( length(pattern) > 0 )
+);
+
+CREATE UNIQUE INDEX idx_blocklist_rules_kind_pattern ON blocklist_rules(kind, pattern);
+
+COMMENT ON TABLE blocklist_rules IS 'Destinations that can not be shortened or redirected to';
+COMMENT ON COLUMN blocklist_rules.pattern IS 'domain: example.com, wildcard: example.com (matches subdomains), regex: matched against host + path';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS blocklist_rules;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE urls ADD COLUMN max_clicks INTEGER;
+ALTER TABLE urls ADD COLUMN clicks_used INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE urls ADD CONSTRAINT valid_max_clicks CHECK ( max_clicks IS NULL OR max_clicks > 0 );
+
+COMMENT ON COLUMN urls.max_clicks IS 'Resolutions allowed before link behaves as expired, NULL for unlimited';
+COMMENT ON COLUMN urls.clicks_used IS 'Resolutions spent from max_clicks';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE urls DROP CONSTRAINT IF EXISTS valid_max_clicks;
+ALTER TABLE urls DROP COLUMN IF EXISTS clicks_used;
+ALTER TABLE urls DROP COLUMN IF EXISTS max_clicks;
+-- +goose StatementEnd
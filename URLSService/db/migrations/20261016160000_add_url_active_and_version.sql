@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE urls ADD COLUMN active BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE urls ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN urls.active IS 'Owner switch, inactive links do not resolve';
+COMMENT ON COLUMN urls.version IS 'Bumped on every edit, used as ETag for optimistic concurrency';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE urls DROP COLUMN IF EXISTS version;
+ALTER TABLE urls DROP COLUMN IF EXISTS active;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX idx_urls_user_id_created_at_short_code ON urls(user_id, created_at DESC, short_code DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_urls_user_id_created_at_short_code;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Host part of destination, bracketed IPv6 literals are kept whole
+CREATE OR REPLACE FUNCTION urls_destination_host(original_url TEXT) RETURNS TEXT AS $$
+    SELECT lower(substring(original_url FROM '^[a-zA-Z][a-zA-Z0-9+.-]*://(\[[^]]+\]|[^/:?#]+)'))
+$$ LANGUAGE SQL IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION urls_set_destination_host() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.destination_host := urls_destination_host(NEW.original_url);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Plain nullable column is a catalog-only change, existing rows are backfilled by the next migration
+ALTER TABLE urls ADD COLUMN destination_host TEXT;
+
+CREATE TRIGGER trg_urls_set_destination_host
+    BEFORE INSERT OR UPDATE OF original_url ON urls
+    FOR EACH ROW EXECUTE FUNCTION urls_set_destination_host();
+
+COMMENT ON COLUMN urls.destination_host IS 'Lowercased host of original_url, kept by trigger, used by list filters';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS trg_urls_set_destination_host ON urls;
+ALTER TABLE urls DROP COLUMN IF EXISTS destination_host;
+DROP FUNCTION IF EXISTS urls_set_destination_host();
+DROP FUNCTION IF EXISTS urls_destination_host(TEXT);
+-- +goose StatementEnd
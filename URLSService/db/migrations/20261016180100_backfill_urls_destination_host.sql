@@ -0,0 +1,30 @@
+-- +goose NO TRANSACTION
+
+-- +goose Up
+-- +goose StatementBegin
+-- Batches are committed one by one so rows are never locked for the whole backfill
+DO $$
+DECLARE
+    last_id UUID := '00000000-0000-0000-0000-000000000000';
+BEGIN
+    LOOP
+        WITH batch AS (
+            SELECT id FROM urls WHERE id > last_id ORDER BY id LIMIT 5000
+        ), updated AS (
+            UPDATE urls SET destination_host = urls_destination_host(urls.original_url)
+            FROM batch
+            WHERE urls.id = batch.id AND urls.destination_host IS NULL
+        )
+        SELECT id INTO last_id FROM batch ORDER BY id DESC LIMIT 1;
+
+        EXIT WHEN last_id IS NULL;
+        COMMIT;
+    END LOOP;
+END
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE urls SET destination_host = NULL;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose NO TRANSACTION
+
+-- +goose Up
+-- Host filter matches the host itself and its subdomains, reversed host turns
+-- the subdomain suffix match into a prefix one the btree can serve
+-- +goose StatementBegin
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_urls_user_id_destination_host_reversed
+    ON urls(user_id, reverse(destination_host) text_pattern_ops);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_urls_user_id_expires_at_short_code ON urls(user_id, expires_at, short_code);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_urls_original_url_trgm ON urls USING GIN (original_url gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_urls_short_code_trgm ON urls USING GIN (short_code gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX CONCURRENTLY IF EXISTS idx_urls_short_code_trgm;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX CONCURRENTLY IF EXISTS idx_urls_original_url_trgm;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX CONCURRENTLY IF EXISTS idx_urls_user_id_expires_at_short_code;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX CONCURRENTLY IF EXISTS idx_urls_user_id_destination_host_reversed;
+-- +goose StatementEnd
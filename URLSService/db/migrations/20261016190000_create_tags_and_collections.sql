@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id VARCHAR(64) NOT NULL,
+    name VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_tags_user_id_name ON tags(user_id, lower(name));
+
+CREATE TABLE IF NOT EXISTS url_tags (
+    url_id UUID NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    tag_id BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+
+    PRIMARY KEY (url_id, tag_id)
+);
+
+CREATE INDEX idx_url_tags_tag_id ON url_tags(tag_id);
+
+CREATE TABLE IF NOT EXISTS collections (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id VARCHAR(64) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_collections_user_id_name ON collections(user_id, lower(name));
+
+ALTER TABLE urls ADD COLUMN collection_id BIGINT REFERENCES collections(id) ON DELETE SET NULL;
+CREATE INDEX idx_urls_collection_id ON urls(collection_id);
+
+COMMENT ON TABLE tags IS 'Per user link labels, names are unique case-insensitively';
+COMMENT ON TABLE collections IS 'Per user named groups, a link belongs to at most one';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_urls_collection_id;
+ALTER TABLE urls DROP COLUMN IF EXISTS collection_id;
+DROP TABLE IF EXISTS collections;
+DROP TABLE IF EXISTS url_tags;
+DROP TABLE IF EXISTS tags;
+-- +goose StatementEnd
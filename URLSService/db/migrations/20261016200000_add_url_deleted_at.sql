@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE urls ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Trash listing and trash purge, live rows are not indexed
+CREATE INDEX idx_urls_user_id_deleted_at ON urls(user_id, deleted_at DESC, short_code DESC) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_urls_deleted_at ON urls(deleted_at) WHERE deleted_at IS NOT NULL;
+
+COMMENT ON COLUMN urls.deleted_at IS 'Set when owner moves link to trash, trashed links keep their short code until purged';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_urls_deleted_at;
+DROP INDEX IF EXISTS idx_urls_user_id_deleted_at;
+ALTER TABLE urls DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
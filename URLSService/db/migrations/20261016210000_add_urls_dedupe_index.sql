@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Destination lookup for dedupe, hashed since original_url can exceed btree entry size
+CREATE INDEX idx_urls_user_id_original_url_md5 ON urls(user_id, md5(original_url)) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_urls_user_id_original_url_md5;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Source numbers for sequence strategy, codes are obfuscated in the service so order doesn't leak
+CREATE SEQUENCE short_code_seq AS BIGINT START WITH 1 CACHE 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP SEQUENCE IF EXISTS short_code_seq;
+-- +goose StatementEnd
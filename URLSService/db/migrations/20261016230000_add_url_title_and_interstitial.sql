@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE urls ADD COLUMN title VARCHAR(200);
+ALTER TABLE urls ADD COLUMN interstitial BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN urls.title IS 'Owner provided title shown on preview page';
+COMMENT ON COLUMN urls.interstitial IS 'Visitors see a warning page before being redirected';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE urls DROP COLUMN IF EXISTS interstitial;
+ALTER TABLE urls DROP COLUMN IF EXISTS title;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS url_metadata (
+    url_id UUID PRIMARY KEY REFERENCES urls(id) ON DELETE CASCADE,
+    source_url TEXT NOT NULL,
+    page_title VARCHAR(300),
+    page_description VARCHAR(1000),
+    image_url VARCHAR(2048),
+    site_name VARCHAR(200),
+    favicon_url VARCHAR(2048),
+    fetch_error VARCHAR(200),
+    fetched_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_url_metadata_page_title_trgm ON url_metadata USING GIN (page_title gin_trgm_ops);
+CREATE INDEX idx_urls_title_trgm ON urls USING GIN (title gin_trgm_ops);
+
+COMMENT ON TABLE url_metadata IS 'Destination page details fetched in background, stale once source_url differs from urls.original_url';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_urls_title_trgm;
+DROP TABLE IF EXISTS url_metadata;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS url_health (
+    url_id UUID PRIMARY KEY REFERENCES urls(id) ON DELETE CASCADE,
+    source_url TEXT NOT NULL,
+    status_code INTEGER,
+    last_error VARCHAR(200),
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_url_health_checked_at ON url_health(checked_at);
+
+COMMENT ON TABLE url_health IS 'Last destination check of links, stale once source_url differs from urls.original_url';
+COMMENT ON COLUMN url_health.status_code IS 'Final response status after redirects, NULL when destination did not respond';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS url_health;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE urls ADD COLUMN targets JSONB;
+
+COMMENT ON COLUMN urls.targets IS 'Ordered device and country targeting rules, original_url is the default destination';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE urls DROP COLUMN IF EXISTS targets;
+-- +goose StatementEnd
@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/cache"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentRequestBytes = 8 << 20
+)
+
+// replayedHeaders are response headers stored together with body
+var replayedHeaders = []string{"Content-Type", "ETag", "Location", "Retry-After"}
+
+// Idempotency replays stored response for requests repeating Idempotency-Key header.
+// Keys are scoped to user and route. Responses with 5xx status are not stored,
+// so such requests can be retried. Requests without the header are passed through,
+// as are anonymous ones: there is no stable scope for them, client address changes
+// between retries and is shared behind NAT, where one client would be replayed another's link.
+func Idempotency(store cache.IdempotencyStore, ttl time.Duration, lockTTL time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			key := r.Header.Get(IdempotencyKeyHeader)
+			userID := r.Header.Get("X-User-Id")
+			if key == "" || userID == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				respondWithIdempotencyError(w, http.StatusBadRequest, "Invalid idempotency key", "key must be at most 255 characters")
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentRequestBytes))
+			if err != nil {
+				respondWithIdempotencyError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			storeKey := hashParts(userID, r.URL.Path, key)
+			fingerprint := hashParts(r.Method, r.URL.Path, string(body))
+
+			stored, err := store.Begin(ctx, storeKey, fingerprint, lockTTL)
+			switch {
+			case errors.Is(err, cache.ErrIdempotencyInProgress):
+				w.Header().Set("Retry-After", "1")
+				respondWithIdempotencyError(w, http.StatusConflict, "Request in progress", err.Error())
+				return
+			case errors.Is(err, cache.ErrIdempotencyKeyReused):
+				respondWithIdempotencyError(w, http.StatusUnprocessableEntity, "Idempotency key reused", err.Error())
+				return
+			case err != nil:
+				// Store outage shouldn't take creation down, request runs without protection
+				logger.RedisLogErrorCtx(ctx, "Idempotency store unavailable", zap.Error(err))
+				next.ServeHTTP(w, r)
+				return
+			case stored != nil:
+				for name, values := range stored.Header {
+					w.Header()[name] = values
+				}
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				w.WriteHeader(stored.Status)
+				_, _ = w.Write(stored.Body)
+				return
+			}
+
+			rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			// Stored even if client went away, the retry must see this outcome
+			storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+			defer cancel()
+
+			if rec.status >= http.StatusInternalServerError {
+				if err := store.Abort(storeCtx, storeKey, fingerprint); err != nil {
+					logger.RedisLogErrorCtx(ctx, "Failed to release idempotency key", zap.Error(err))
+				}
+				return
+			}
+
+			header := make(http.Header)
+			for _, name := range replayedHeaders {
+				if values := w.Header().Values(name); len(values) > 0 {
+					header[name] = values
+				}
+			}
+			response := &cache.StoredResponse{Status: rec.status, Header: header, Body: rec.body.Bytes()}
+			if err := store.Complete(storeCtx, storeKey, fingerprint, response, ttl); err != nil {
+				logger.RedisLogErrorCtx(ctx, "Failed to store idempotent response", zap.Error(err))
+			}
+		})
+	}
+}
+
+// responseRecorder passes response through and keeps a copy of it
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status = status
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// hashParts hashes parts separated unambiguously
+func hashParts(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		_, _ = io.WriteString(h, part)
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// respondWithIdempotencyError writes error in the same shape as API handlers do
+func respondWithIdempotencyError(w http.ResponseWriter, status int, title string, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": title, "message": message})
+}
@@ -2,8 +2,11 @@ package v1
 
 import (
 	"sync/atomic"
+	"time"
 
 	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/api/middleware"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/cache"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/config"
 	v1 "github.com/ArtemBorodinEvgenyevich/URLSService/internal/handler/v1"
 	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/service"
 	"github.com/go-chi/chi/v5"
@@ -13,17 +16,32 @@ import (
 
 // Config holds dependencies needed for v1 API routes
 type Config struct {
-	URLService   service.URLService
-	PgPool       *pgxpool.Pool
-	RedisClient  *redis.Client
-	ShuttingDown *atomic.Bool
+	URLService       service.URLService
+	LabelService     service.LabelService
+	AnalyticsService service.AnalyticsService
+	LinkHealth       service.LinkHealthService
+	PgPool           *pgxpool.Pool
+	RedisClient      *redis.Client
+	ClickLag         v1.LagReporter
+	ClickDrops       v1.DropReporter
+	ShuttingDown     *atomic.Bool
+	Idempotency      cache.IdempotencyStore
+	IdempotencyTTL   time.Duration
+	IdempotencyLock  time.Duration
+	QR               *config.QRConfig
+	Redirect         *config.RedirectConfig
+	GeoIP            *config.GeoIPConfig
 }
 
 // RegisterRoutes registers all v1 API routes
 func RegisterRoutes(r chi.Router, cfg *Config) {
 	// Initialize handlers
-	urlHandler := v1.NewURLHandler(cfg.URLService)
-	healthHandler := v1.NewHealthHandler(cfg.PgPool, cfg.RedisClient, cfg.ShuttingDown)
+	urlHandler := v1.NewURLHandler(cfg.URLService, cfg.GeoIP)
+	labelHandler := v1.NewLabelHandler(cfg.LabelService)
+	analyticsHandler := v1.NewAnalyticsHandler(cfg.AnalyticsService)
+	linkHealthHandler := v1.NewLinkHealthHandler(cfg.LinkHealth)
+	qrHandler := v1.NewQRHandler(cfg.URLService, cfg.QR, cfg.Redirect)
+	healthHandler := v1.NewHealthHandler(cfg.PgPool, cfg.RedisClient, cfg.ClickLag, cfg.ClickDrops, cfg.ShuttingDown)
 
 	// API v1 group
 	r.Route("/api/v1", func(r chi.Router) {
@@ -36,9 +54,30 @@ func RegisterRoutes(r chi.Router, cfg *Config) {
 		r.Get("/readiness", healthHandler.ReadinessCheck)
 
 		// URL shortener endpoints
-		r.Post("/shorten", urlHandler.Create)
+		// Create endpoints replay responses of retries carrying the same Idempotency-Key
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Idempotency(cfg.Idempotency, cfg.IdempotencyTTL, cfg.IdempotencyLock))
+			r.Post("/shorten", urlHandler.Create)
+			r.Post("/shorten/batch", urlHandler.CreateBatch)
+		})
 		r.Get("/urls", urlHandler.List)
+		r.Get("/urls/trash", urlHandler.ListTrash)
 		r.Get("/urls/{shortCode}", urlHandler.Get)
+		r.Get("/urls/{shortCode}/stats", analyticsHandler.Stats)
+		r.Get("/urls/{shortCode}/health", linkHealthHandler.Health)
+		r.Get("/urls/{shortCode}/qr", qrHandler.QR)
+		r.Patch("/urls/{shortCode}", urlHandler.Update)
 		r.Delete("/urls/{shortCode}", urlHandler.Delete)
+		r.Post("/urls/{shortCode}/restore", urlHandler.Restore)
+		r.Put("/urls/{shortCode}/tags", labelHandler.SetURLTags)
+		r.Put("/urls/{shortCode}/collection", labelHandler.SetURLCollection)
+
+		// Owner's labels
+		r.Get("/tags", labelHandler.ListTags)
+		r.Patch("/tags/{name}", labelHandler.RenameTag)
+		r.Delete("/tags/{name}", labelHandler.DeleteTag)
+		r.Get("/collections", labelHandler.ListCollections)
+		r.Patch("/collections/{name}", labelHandler.RenameCollection)
+		r.Delete("/collections/{name}", labelHandler.DeleteCollection)
 	})
 }
@@ -0,0 +1,29 @@
+package web
+
+import (
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/config"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/handler/web"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// Config holds dependencies needed for public (non-API) routes
+type Config struct {
+	URLService service.URLService
+	Redirect   *config.RedirectConfig
+	GeoIP      *config.GeoIPConfig
+}
+
+// RegisterRoutes registers public short link routes at the root path
+func RegisterRoutes(r chi.Router, cfg *Config) {
+	redirectHandler := web.NewRedirectHandler(cfg.URLService, cfg.Redirect, cfg.GeoIP)
+	previewHandler := web.NewPreviewHandler(cfg.URLService)
+
+	r.Get("/{shortCode}", redirectHandler.Redirect)
+	r.Head("/{shortCode}", redirectHandler.Redirect)
+	r.Post("/{shortCode}", redirectHandler.Redirect)
+
+	// Trailing "+" shows where link leads instead of following it
+	r.Get("/{shortCode}+", previewHandler.Preview)
+	r.Head("/{shortCode}+", previewHandler.Preview)
+}
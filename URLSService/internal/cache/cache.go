@@ -2,6 +2,8 @@ package cache
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"time"
 
 	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
@@ -10,6 +12,54 @@ import (
 type URLCache interface {
 	Get(ctx context.Context, shortCode string) (*domain.URL, error)
 	Set(ctx context.Context, shortCode string, url *domain.URL, ttl time.Duration) error
+	// SetMany caches URLs in one round trip, each until it expires
+	SetMany(ctx context.Context, urls []*domain.URL) error
 	Delete(ctx context.Context, shortCode string) error
 	SetNegativeCache(ctx context.Context, shortcode string) error
+	SetExpiredCache(ctx context.Context, shortCode string) error
+}
+
+// ClickCounter keeps shared click budget of click-limited links across replicas
+type ClickCounter interface {
+	// Take seeds counter with clicks left on url if it's absent and spends one,
+	// negative result means link has no clicks left
+	Take(ctx context.Context, url *domain.URL) (int64, error)
+	// Refund gives back click taken for a resolution that failed afterwards
+	Refund(ctx context.Context, url *domain.URL) error
+}
+
+var (
+	// ErrIdempotencyInProgress means request with the same key hasn't finished yet
+	ErrIdempotencyInProgress = errors.New("request with this idempotency key is in progress")
+	// ErrIdempotencyKeyReused means the key was used for a request with another body
+	ErrIdempotencyKeyReused = errors.New("idempotency key was used for another request")
+)
+
+// StoredResponse is a response saved under idempotency key to be replayed as is
+type StoredResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// IdempotencyStore remembers responses of non-idempotent requests by client supplied key
+type IdempotencyStore interface {
+	// Begin reserves key for lockTTL and returns nil, or returns response stored for the key.
+	// Fingerprint identifies request body, other requests under the same key are rejected.
+	Begin(ctx context.Context, key string, fingerprint string, lockTTL time.Duration) (*StoredResponse, error)
+	// Complete stores response under reserved key for ttl
+	Complete(ctx context.Context, key string, fingerprint string, response *StoredResponse, ttl time.Duration) error
+	// Abort releases reserved key, so the request can be retried
+	Abort(ctx context.Context, key string, fingerprint string) error
+}
+
+// ErrCodePoolEmpty means there is no pre-generated short code to claim
+var ErrCodePoolEmpty = errors.New("short code pool is empty")
+
+// CodePool keeps pre-generated unused short codes shared by replicas
+type CodePool interface {
+	// Claim atomically takes one code out of pool, ErrCodePoolEmpty when there is none
+	Claim(ctx context.Context) (string, error)
+	Add(ctx context.Context, shortCodes []string) error
+	Size(ctx context.Context) (int64, error)
 }
@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/cache"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+const clicksLeftKeyPrefix = "clicksleft:"
+
+// takeScript seeds counter only when it is missing, so replicas never reset each other
+var takeScript = redis.NewScript(`
+redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2])
+return redis.call('DECR', KEYS[1])
+`)
+
+// refundScript doesn't recreate expired counter, it would lose its TTL
+var refundScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return redis.call('INCR', KEYS[1])
+end
+return 0
+`)
+
+type clickCounter struct {
+	client *redis.Client
+}
+
+func NewClickCounter(client *redis.Client) cache.ClickCounter {
+	return &clickCounter{client: client}
+}
+
+func (c *clickCounter) Take(ctx context.Context, url *domain.URL) (int64, error) {
+	ttl := time.Until(url.ExpiresAt)
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+
+	left, err := takeScript.Run(ctx, c.client,
+		[]string{clicksLeftKey(url)},
+		url.MaxClicks-url.ClicksUsed,
+		ttl.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("redis click counter error: %w", err)
+	}
+
+	return left, nil
+}
+
+func (c *clickCounter) Refund(ctx context.Context, url *domain.URL) error {
+	if err := refundScript.Run(ctx, c.client, []string{clicksLeftKey(url)}).Err(); err != nil {
+		return fmt.Errorf("redis click refund error: %w", err)
+	}
+
+	return nil
+}
+
+// clicksLeftKey includes creation time, so a deleted code taken again gets a fresh counter.
+// Microseconds match what Postgres keeps, cached and freshly read URLs share the key.
+func clicksLeftKey(url *domain.URL) string {
+	return clicksLeftKeyPrefix + url.ShortCode + ":" + strconv.FormatInt(url.CreatedAt.UnixMicro(), 36)
+}
@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+const codePoolKey = "shortcodes:pool"
+
+// codePool is a Redis set, SPOP hands every code to exactly one replica
+type codePool struct {
+	client *redis.Client
+}
+
+func NewCodePool(client *redis.Client) cache.CodePool {
+	return &codePool{client: client}
+}
+
+func (p *codePool) Claim(ctx context.Context) (string, error) {
+	shortCode, err := p.client.SPop(ctx, codePoolKey).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", cache.ErrCodePoolEmpty
+		}
+		return "", fmt.Errorf("redis code pool claim error: %w", err)
+	}
+
+	return shortCode, nil
+}
+
+func (p *codePool) Add(ctx context.Context, shortCodes []string) error {
+	if len(shortCodes) == 0 {
+		return nil
+	}
+
+	members := make([]any, len(shortCodes))
+	for i, shortCode := range shortCodes {
+		members[i] = shortCode
+	}
+	if err := p.client.SAdd(ctx, codePoolKey, members...).Err(); err != nil {
+		return fmt.Errorf("redis code pool add error: %w", err)
+	}
+
+	return nil
+}
+
+func (p *codePool) Size(ctx context.Context) (int64, error) {
+	size, err := p.client.SCard(ctx, codePoolKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis code pool size error: %w", err)
+	}
+
+	return size, nil
+}
@@ -0,0 +1,103 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+const idempotencyKeyPrefix = "idempotency:"
+
+// abortScript releases key only while it still holds our reservation
+var abortScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// idempotencyEntry is either a reservation, without response, or a finished request
+type idempotencyEntry struct {
+	Fingerprint string                `json:"fingerprint"`
+	Response    *cache.StoredResponse `json:"response,omitempty"`
+}
+
+type idempotencyStore struct {
+	client *redis.Client
+}
+
+func NewIdempotencyStore(client *redis.Client) cache.IdempotencyStore {
+	return &idempotencyStore{client: client}
+}
+
+func (s *idempotencyStore) Begin(ctx context.Context, key string, fingerprint string, lockTTL time.Duration) (*cache.StoredResponse, error) {
+	reservation, err := json.Marshal(idempotencyEntry{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal idempotency entry: %w", err)
+	}
+
+	// Second round covers entry expiring between SET and GET
+	for range 2 {
+		reserved, err := s.client.SetNX(ctx, idempotencyKeyPrefix+key, reservation, lockTTL).Result()
+		if err != nil {
+			return nil, fmt.Errorf("redis idempotency error: %w", err)
+		}
+		if reserved {
+			return nil, nil
+		}
+
+		data, err := s.client.Get(ctx, idempotencyKeyPrefix+key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("redis idempotency error: %w", err)
+		}
+
+		var entry idempotencyEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal idempotency entry: %w", err)
+		}
+		switch {
+		case entry.Fingerprint != fingerprint:
+			return nil, cache.ErrIdempotencyKeyReused
+		case entry.Response == nil:
+			return nil, cache.ErrIdempotencyInProgress
+		default:
+			return entry.Response, nil
+		}
+	}
+
+	return nil, cache.ErrIdempotencyInProgress
+}
+
+func (s *idempotencyStore) Complete(ctx context.Context, key string, fingerprint string, response *cache.StoredResponse, ttl time.Duration) error {
+	data, err := json.Marshal(idempotencyEntry{Fingerprint: fingerprint, Response: response})
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency entry: %w", err)
+	}
+
+	if err := s.client.Set(ctx, idempotencyKeyPrefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("redis idempotency error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *idempotencyStore) Abort(ctx context.Context, key string, fingerprint string) error {
+	reservation, err := json.Marshal(idempotencyEntry{Fingerprint: fingerprint})
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency entry: %w", err)
+	}
+
+	if err := abortScript.Run(ctx, s.client, []string{idempotencyKeyPrefix + key}, reservation).Err(); err != nil {
+		return fmt.Errorf("redis idempotency error: %w", err)
+	}
+
+	return nil
+}
@@ -23,10 +23,14 @@ const (
 	notFoundKeyPrefix = "notfound:"
 	defaultTTL        = 1 * time.Hour
 	notFoundTTL       = 5 * time.Minute
+
+	// expiredMarker is stored under the notfound key to tell expired links from unknown ones
+	expiredMarker = "expired"
 )
 
 var ErrCacheMiss = errors.New("cache miss")
 var ErrNegativeCached = errors.New("url doesn't exist")
+var ErrExpiredCached = errors.New("url expired")
 
 type urlCache struct {
 	client *redis.Client
@@ -44,7 +48,10 @@ func (u *urlCache) Get(ctx context.Context, shortCode string) (*domain.URL, erro
 	if err != nil {
 		return nil, fmt.Errorf("redis mget error: %w", err)
 	}
-	if rdbMGetRes[NotFoundCacheKey] != nil {
+	if marker := rdbMGetRes[NotFoundCacheKey]; marker != nil {
+		if marker == expiredMarker {
+			return nil, ErrExpiredCached
+		}
 		return nil, ErrNegativeCached
 	}
 	if rdbMGetRes[URLCacheKey] == nil {
@@ -86,6 +93,34 @@ func (u *urlCache) Set(ctx context.Context, shortCode string, url *domain.URL, t
 	return nil
 }
 
+func (u *urlCache) SetMany(ctx context.Context, urls []*domain.URL) error {
+	pipe := u.client.Pipeline()
+	for _, url := range urls {
+		ttl := time.Until(url.ExpiresAt)
+		if ttl <= 0 {
+			continue
+		}
+
+		data, err := json.Marshal(url)
+		if err != nil {
+			return fmt.Errorf("failed to marshal cached URL: %w", err)
+		}
+
+		keys := createCacheKeys(url.ShortCode)
+		pipe.Del(ctx, keys[NotFoundCacheKey])
+		pipe.Set(ctx, keys[URLCacheKey], data, ttl)
+	}
+
+	if pipe.Len() == 0 {
+		return nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis pipeline error: %w", err)
+	}
+
+	return nil
+}
+
 func (u *urlCache) Delete(ctx context.Context, shortCode string) error {
 	key := createCacheKeys(shortCode)
 	keyVals := cacheKeysValues(key)
@@ -107,6 +142,20 @@ func (u *urlCache) SetNegativeCache(ctx context.Context, shortCode string) error
 	return nil
 }
 
+func (u *urlCache) SetExpiredCache(ctx context.Context, shortCode string) error {
+	keys := createCacheKeys(shortCode)
+
+	pipe := u.client.Pipeline()
+	pipe.Del(ctx, keys[URLCacheKey])
+	pipe.Set(ctx, keys[NotFoundCacheKey], expiredMarker, notFoundTTL)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis set expired cache error: %w", err)
+	}
+
+	return nil
+}
+
 func (u *urlCache) cacheKeyExists(ctx context.Context, key string) (bool, error) {
 	exists, err := u.client.Exists(ctx, key).Result()
 	if err != nil {
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Blocklist rule sources
+const (
+	BlocklistSourceNone     = "none"
+	BlocklistSourceFile     = "file"
+	BlocklistSourcePostgres = "postgres"
+)
+
+// BlocklistConfig params for destination domain blocklist.
+type BlocklistConfig struct {
+	source         string
+	filePath       string
+	reloadInterval time.Duration
+}
+
+func (c *BlocklistConfig) Source() string {
+	return c.source
+}
+
+func (c *BlocklistConfig) FilePath() string {
+	return c.filePath
+}
+
+func (c *BlocklistConfig) ReloadInterval() time.Duration {
+	return c.reloadInterval
+}
+
+// BlocklistConfigBuilder builds BlocklistConfig with validation on each step.
+type BlocklistConfigBuilder struct {
+	config BlocklistConfig
+	errors []error
+}
+
+// NewBlocklistConfigBuilder creates new builder with default values.
+func NewBlocklistConfigBuilder() *BlocklistConfigBuilder {
+	return &BlocklistConfigBuilder{
+		config: BlocklistConfig{
+			source:         BlocklistSourceNone,
+			reloadInterval: 1 * time.Minute,
+		},
+		errors: make([]error, 0),
+	}
+}
+
+// WithSource sets where rules are loaded from: none, file or postgres.
+func (b *BlocklistConfigBuilder) WithSource(source string) *BlocklistConfigBuilder {
+	switch source {
+	case BlocklistSourceNone, BlocklistSourceFile, BlocklistSourcePostgres:
+		b.config.source = source
+	default:
+		b.errors = append(b.errors, fmt.Errorf("blocklist source must be one of none, file, postgres, got %q", source))
+	}
+	return b
+}
+
+// WithFilePath sets path to rules file, used with file source.
+func (b *BlocklistConfigBuilder) WithFilePath(path string) *BlocklistConfigBuilder {
+	b.config.filePath = path
+	return b
+}
+
+// WithReloadInterval sets how often rules are reloaded, 0 reloads only on SIGHUP.
+func (b *BlocklistConfigBuilder) WithReloadInterval(interval time.Duration) *BlocklistConfigBuilder {
+	if interval < 0 {
+		b.errors = append(b.errors, fmt.Errorf("blocklist reload interval can't be negative, got %v", interval))
+		return b
+	}
+	b.config.reloadInterval = interval
+	return b
+}
+
+// Build creates BlocklistConfig with checking for errors.
+func (b *BlocklistConfigBuilder) Build() (*BlocklistConfig, error) {
+	if b.config.source == BlocklistSourceFile && b.config.filePath == "" {
+		b.errors = append(b.errors, fmt.Errorf("blocklist file path is required for file source"))
+	}
+
+	if len(b.errors) > 0 {
+		return nil, fmt.Errorf("configuration errors: %v", b.errors)
+	}
+
+	return &b.config, nil
+}
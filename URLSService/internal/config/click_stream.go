@@ -0,0 +1,190 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// ClickStreamConfig params for click events pipeline over Redis Streams.
+type ClickStreamConfig struct {
+	// Stream params
+	name     string
+	group    string
+	consumer string
+	maxLen   int64
+
+	// publishTimeout bounds XADD made while redirect is served
+	publishTimeout time.Duration
+
+	// Consumer params
+	batchSize     int64
+	blockTimeout  time.Duration
+	claimIdle     time.Duration
+	claimInterval time.Duration
+}
+
+func (c *ClickStreamConfig) Name() string {
+	return c.name
+}
+
+func (c *ClickStreamConfig) Group() string {
+	return c.group
+}
+
+func (c *ClickStreamConfig) Consumer() string {
+	return c.consumer
+}
+
+func (c *ClickStreamConfig) MaxLen() int64 {
+	return c.maxLen
+}
+
+func (c *ClickStreamConfig) PublishTimeout() time.Duration {
+	return c.publishTimeout
+}
+
+func (c *ClickStreamConfig) BatchSize() int64 {
+	return c.batchSize
+}
+
+func (c *ClickStreamConfig) BlockTimeout() time.Duration {
+	return c.blockTimeout
+}
+
+func (c *ClickStreamConfig) ClaimIdle() time.Duration {
+	return c.claimIdle
+}
+
+func (c *ClickStreamConfig) ClaimInterval() time.Duration {
+	return c.claimInterval
+}
+
+// ClickStreamConfigBuilder builds ClickStreamConfig with validation on each step.
+type ClickStreamConfigBuilder struct {
+	config ClickStreamConfig
+	errors []error
+}
+
+// NewClickStreamConfigBuilder creates new builder with default values.
+// Consumer name defaults to hostname, which is the pod name in k8s.
+func NewClickStreamConfigBuilder() *ClickStreamConfigBuilder {
+	consumer, err := os.Hostname()
+	if err != nil || consumer == "" {
+		consumer = "urls-service"
+	}
+
+	return &ClickStreamConfigBuilder{
+		config: ClickStreamConfig{
+			name:           "clicks",
+			group:          "clicks-writers",
+			consumer:       consumer,
+			maxLen:         1_000_000,
+			publishTimeout: 250 * time.Millisecond,
+			batchSize:      500,
+			blockTimeout:   2 * time.Second,
+			claimIdle:      1 * time.Minute,
+			claimInterval:  30 * time.Second,
+		},
+		errors: make([]error, 0),
+	}
+}
+
+// WithName sets stream key.
+func (b *ClickStreamConfigBuilder) WithName(name string) *ClickStreamConfigBuilder {
+	if name == "" {
+		b.errors = append(b.errors, fmt.Errorf("click stream name cannot be empty"))
+		return b
+	}
+	b.config.name = name
+	return b
+}
+
+// WithGroup sets consumer group shared by all replicas.
+func (b *ClickStreamConfigBuilder) WithGroup(group string) *ClickStreamConfigBuilder {
+	if group == "" {
+		b.errors = append(b.errors, fmt.Errorf("click stream group cannot be empty"))
+		return b
+	}
+	b.config.group = group
+	return b
+}
+
+// WithConsumer sets consumer name, must be unique per replica.
+func (b *ClickStreamConfigBuilder) WithConsumer(consumer string) *ClickStreamConfigBuilder {
+	if consumer == "" {
+		b.errors = append(b.errors, fmt.Errorf("click stream consumer cannot be empty"))
+		return b
+	}
+	b.config.consumer = consumer
+	return b
+}
+
+// WithMaxLen sets approximate stream length cap.
+func (b *ClickStreamConfigBuilder) WithMaxLen(maxLen int64) *ClickStreamConfigBuilder {
+	if maxLen <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("click stream max length must be positive, got %d", maxLen))
+		return b
+	}
+	b.config.maxLen = maxLen
+	return b
+}
+
+// WithPublishTimeout sets how long redirect waits for its click to be added to the stream.
+func (b *ClickStreamConfigBuilder) WithPublishTimeout(timeout time.Duration) *ClickStreamConfigBuilder {
+	if timeout <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("click stream publish timeout must be positive, got %v", timeout))
+		return b
+	}
+	b.config.publishTimeout = timeout
+	return b
+}
+
+// WithBatchSize sets max entries written to PostgreSQL at once.
+func (b *ClickStreamConfigBuilder) WithBatchSize(batchSize int64) *ClickStreamConfigBuilder {
+	if batchSize <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("click stream batch size must be positive, got %d", batchSize))
+		return b
+	}
+	b.config.batchSize = batchSize
+	return b
+}
+
+// WithBlockTimeout sets how long consumer waits for new entries.
+func (b *ClickStreamConfigBuilder) WithBlockTimeout(timeout time.Duration) *ClickStreamConfigBuilder {
+	if timeout <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("click stream block timeout must be positive, got %v", timeout))
+		return b
+	}
+	b.config.blockTimeout = timeout
+	return b
+}
+
+// WithClaimIdle sets how long entry stays unacknowledged before another consumer takes it over.
+func (b *ClickStreamConfigBuilder) WithClaimIdle(idle time.Duration) *ClickStreamConfigBuilder {
+	if idle <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("click stream claim idle must be positive, got %v", idle))
+		return b
+	}
+	b.config.claimIdle = idle
+	return b
+}
+
+// WithClaimInterval sets how often consumer looks for abandoned entries.
+func (b *ClickStreamConfigBuilder) WithClaimInterval(interval time.Duration) *ClickStreamConfigBuilder {
+	if interval <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("click stream claim interval must be positive, got %v", interval))
+		return b
+	}
+	b.config.claimInterval = interval
+	return b
+}
+
+// Build creates ClickStreamConfig with checking for errors.
+func (b *ClickStreamConfigBuilder) Build() (*ClickStreamConfig, error) {
+	if len(b.errors) > 0 {
+		return nil, fmt.Errorf("configuration errors: %v", b.errors)
+	}
+
+	return &b.config, nil
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxCodePoolBatchSize bounds codes checked against urls table by one query
+const maxCodePoolBatchSize = 10000
+
+// CodePoolConfig params for pool of pre-generated short codes.
+type CodePoolConfig struct {
+	enabled      bool
+	size         int
+	lowWatermark int
+	batchSize    int
+	interval     time.Duration
+}
+
+func (c *CodePoolConfig) Enabled() bool {
+	return c.enabled
+}
+
+func (c *CodePoolConfig) Size() int {
+	return c.size
+}
+
+func (c *CodePoolConfig) LowWatermark() int {
+	return c.lowWatermark
+}
+
+func (c *CodePoolConfig) BatchSize() int {
+	return c.batchSize
+}
+
+func (c *CodePoolConfig) Interval() time.Duration {
+	return c.interval
+}
+
+// CodePoolConfigBuilder builds CodePoolConfig with validation on each step.
+type CodePoolConfigBuilder struct {
+	config CodePoolConfig
+	errors []error
+}
+
+// NewCodePoolConfigBuilder creates new builder with default values.
+func NewCodePoolConfigBuilder() *CodePoolConfigBuilder {
+	return &CodePoolConfigBuilder{
+		config: CodePoolConfig{
+			enabled:      false,
+			size:         10000,
+			lowWatermark: 2000,
+			batchSize:    500,
+			interval:     10 * time.Second,
+		},
+		errors: make([]error, 0),
+	}
+}
+
+// WithEnabled turns pool on, codes are generated on create otherwise.
+func (b *CodePoolConfigBuilder) WithEnabled(enabled bool) *CodePoolConfigBuilder {
+	b.config.enabled = enabled
+	return b
+}
+
+// WithSize sets how many codes pool is filled up to.
+func (b *CodePoolConfigBuilder) WithSize(size int) *CodePoolConfigBuilder {
+	if size <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("code pool size must be positive, got %d", size))
+		return b
+	}
+	b.config.size = size
+	return b
+}
+
+// WithLowWatermark sets pool size below which it is refilled.
+func (b *CodePoolConfigBuilder) WithLowWatermark(lowWatermark int) *CodePoolConfigBuilder {
+	if lowWatermark < 0 {
+		b.errors = append(b.errors, fmt.Errorf("code pool low watermark can't be negative, got %d", lowWatermark))
+		return b
+	}
+	b.config.lowWatermark = lowWatermark
+	return b
+}
+
+// WithBatchSize sets how many codes are generated and checked at once.
+func (b *CodePoolConfigBuilder) WithBatchSize(batchSize int) *CodePoolConfigBuilder {
+	if batchSize <= 0 || batchSize > maxCodePoolBatchSize {
+		b.errors = append(b.errors, fmt.Errorf("code pool batch size must be between 1 and %d, got %d", maxCodePoolBatchSize, batchSize))
+		return b
+	}
+	b.config.batchSize = batchSize
+	return b
+}
+
+// WithInterval sets how often pool size is checked.
+func (b *CodePoolConfigBuilder) WithInterval(interval time.Duration) *CodePoolConfigBuilder {
+	if interval <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("code pool interval must be positive, got %v", interval))
+		return b
+	}
+	b.config.interval = interval
+	return b
+}
+
+// Build creates CodePoolConfig with checking for errors.
+func (b *CodePoolConfigBuilder) Build() (*CodePoolConfig, error) {
+	if b.config.lowWatermark >= b.config.size {
+		b.errors = append(b.errors, fmt.Errorf("code pool low watermark %d must be below size %d", b.config.lowWatermark, b.config.size))
+	}
+
+	if len(b.errors) > 0 {
+		return nil, fmt.Errorf("configuration errors: %v", b.errors)
+	}
+
+	return &b.config, nil
+}
@@ -179,6 +179,588 @@ func LoadRedisConfigFromEnv() (*RedisConfig, error) {
 	return builder.Build()
 }
 
+func LoadRedirectConfigFromEnv() (*RedirectConfig, error) {
+	builder := NewRedirectConfigBuilder()
+
+	if statusStr := os.Getenv("REDIRECT_DEFAULT_STATUS"); statusStr != "" {
+		status, err := strconv.Atoi(statusStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REDIRECT_DEFAULT_STATUS: %w", err)
+		}
+		builder.WithDefaultStatus(status)
+	}
+
+	if maxAgeStr := os.Getenv("REDIRECT_CACHE_MAX_AGE"); maxAgeStr != "" {
+		maxAge, err := parseDuration(maxAgeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REDIRECT_CACHE_MAX_AGE: %w", err)
+		}
+		builder.WithCacheMaxAge(maxAge)
+	}
+
+	if baseURL := os.Getenv("REDIRECT_PUBLIC_BASE_URL"); baseURL != "" {
+		builder.WithPublicBaseURL(baseURL)
+	}
+
+	return builder.Build()
+}
+
+func LoadClickStreamConfigFromEnv() (*ClickStreamConfig, error) {
+	builder := NewClickStreamConfigBuilder()
+
+	if name := os.Getenv("CLICK_STREAM_NAME"); name != "" {
+		builder.WithName(name)
+	}
+
+	if group := os.Getenv("CLICK_STREAM_GROUP"); group != "" {
+		builder.WithGroup(group)
+	}
+
+	if consumer := os.Getenv("CLICK_STREAM_CONSUMER"); consumer != "" {
+		builder.WithConsumer(consumer)
+	}
+
+	if maxLenStr := os.Getenv("CLICK_STREAM_MAX_LEN"); maxLenStr != "" {
+		maxLen, err := strconv.ParseInt(maxLenStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CLICK_STREAM_MAX_LEN: %w", err)
+		}
+		builder.WithMaxLen(maxLen)
+	}
+
+	if batchSizeStr := os.Getenv("CLICK_STREAM_BATCH_SIZE"); batchSizeStr != "" {
+		batchSize, err := strconv.ParseInt(batchSizeStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CLICK_STREAM_BATCH_SIZE: %w", err)
+		}
+		builder.WithBatchSize(batchSize)
+	}
+
+	if blockTimeoutStr := os.Getenv("CLICK_STREAM_BLOCK_TIMEOUT"); blockTimeoutStr != "" {
+		timeout, err := parseDuration(blockTimeoutStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CLICK_STREAM_BLOCK_TIMEOUT: %w", err)
+		}
+		builder.WithBlockTimeout(timeout)
+	}
+
+	if claimIdleStr := os.Getenv("CLICK_STREAM_CLAIM_IDLE"); claimIdleStr != "" {
+		idle, err := parseDuration(claimIdleStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CLICK_STREAM_CLAIM_IDLE: %w", err)
+		}
+		builder.WithClaimIdle(idle)
+	}
+
+	if claimIntervalStr := os.Getenv("CLICK_STREAM_CLAIM_INTERVAL"); claimIntervalStr != "" {
+		interval, err := parseDuration(claimIntervalStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CLICK_STREAM_CLAIM_INTERVAL: %w", err)
+		}
+		builder.WithClaimInterval(interval)
+	}
+
+	if timeoutStr := os.Getenv("CLICK_STREAM_PUBLISH_TIMEOUT"); timeoutStr != "" {
+		timeout, err := parseDuration(timeoutStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CLICK_STREAM_PUBLISH_TIMEOUT: %w", err)
+		}
+		builder.WithPublishTimeout(timeout)
+	}
+
+	return builder.Build()
+}
+
+func LoadURLValidationConfigFromEnv() (*URLValidationConfig, error) {
+	builder := NewURLValidationConfigBuilder()
+
+	if maxLengthStr := os.Getenv("URL_MAX_LENGTH"); maxLengthStr != "" {
+		maxLength, err := strconv.Atoi(maxLengthStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL_MAX_LENGTH: %w", err)
+		}
+		builder.WithMaxLength(maxLength)
+	}
+
+	if stripFragmentStr := os.Getenv("URL_STRIP_FRAGMENT"); stripFragmentStr != "" {
+		strip, err := strconv.ParseBool(stripFragmentStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL_STRIP_FRAGMENT: %w", err)
+		}
+		builder.WithStripFragment(strip)
+	}
+
+	if allowPrivateStr := os.Getenv("URL_ALLOW_PRIVATE_HOSTS"); allowPrivateStr != "" {
+		allow, err := strconv.ParseBool(allowPrivateStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL_ALLOW_PRIVATE_HOSTS: %w", err)
+		}
+		builder.WithAllowPrivateHosts(allow)
+	}
+
+	return builder.Build()
+}
+
+func LoadBlocklistConfigFromEnv() (*BlocklistConfig, error) {
+	builder := NewBlocklistConfigBuilder()
+
+	if source := os.Getenv("BLOCKLIST_SOURCE"); source != "" {
+		builder.WithSource(source)
+	}
+
+	if path := os.Getenv("BLOCKLIST_FILE"); path != "" {
+		builder.WithFilePath(path)
+	}
+
+	if intervalStr := os.Getenv("BLOCKLIST_RELOAD_INTERVAL"); intervalStr != "" {
+		interval, err := parseDuration(intervalStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BLOCKLIST_RELOAD_INTERVAL: %w", err)
+		}
+		builder.WithReloadInterval(interval)
+	}
+
+	return builder.Build()
+}
+
+func LoadLinkPasswordConfigFromEnv() (*LinkPasswordConfig, error) {
+	builder := NewLinkPasswordConfigBuilder()
+
+	if maxAttemptsStr := os.Getenv("LINK_PASSWORD_MAX_ATTEMPTS"); maxAttemptsStr != "" {
+		maxAttempts, err := strconv.ParseInt(maxAttemptsStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LINK_PASSWORD_MAX_ATTEMPTS: %w", err)
+		}
+		builder.WithMaxAttempts(maxAttempts)
+	}
+
+	if windowStr := os.Getenv("LINK_PASSWORD_ATTEMPT_WINDOW"); windowStr != "" {
+		window, err := parseDuration(windowStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LINK_PASSWORD_ATTEMPT_WINDOW: %w", err)
+		}
+		builder.WithAttemptWindow(window)
+	}
+
+	if costStr := os.Getenv("LINK_PASSWORD_BCRYPT_COST"); costStr != "" {
+		cost, err := strconv.Atoi(costStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LINK_PASSWORD_BCRYPT_COST: %w", err)
+		}
+		builder.WithBcryptCost(cost)
+	}
+
+	return builder.Build()
+}
+
+func LoadPurgeConfigFromEnv() (*PurgeConfig, error) {
+	builder := NewPurgeConfigBuilder()
+
+	if enabledStr := os.Getenv("URL_PURGE_ENABLED"); enabledStr != "" {
+		enabled, err := strconv.ParseBool(enabledStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL_PURGE_ENABLED: %w", err)
+		}
+		builder.WithEnabled(enabled)
+	}
+
+	if intervalStr := os.Getenv("URL_PURGE_INTERVAL"); intervalStr != "" {
+		interval, err := parseDuration(intervalStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL_PURGE_INTERVAL: %w", err)
+		}
+		builder.WithInterval(interval)
+	}
+
+	if batchSizeStr := os.Getenv("URL_PURGE_BATCH_SIZE"); batchSizeStr != "" {
+		batchSize, err := strconv.Atoi(batchSizeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL_PURGE_BATCH_SIZE: %w", err)
+		}
+		builder.WithBatchSize(batchSize)
+	}
+
+	if pauseStr := os.Getenv("URL_PURGE_BATCH_PAUSE"); pauseStr != "" {
+		pause, err := parseDuration(pauseStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL_PURGE_BATCH_PAUSE: %w", err)
+		}
+		builder.WithBatchPause(pause)
+	}
+
+	if retentionStr := os.Getenv("URL_PURGE_RETENTION"); retentionStr != "" {
+		retention, err := parseDuration(retentionStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL_PURGE_RETENTION: %w", err)
+		}
+		builder.WithRetention(retention)
+	}
+
+	if trashRetentionStr := os.Getenv("URL_TRASH_RETENTION"); trashRetentionStr != "" {
+		trashRetention, err := parseDuration(trashRetentionStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL_TRASH_RETENTION: %w", err)
+		}
+		builder.WithTrashRetention(trashRetention)
+	}
+
+	return builder.Build()
+}
+
+func LoadIdempotencyConfigFromEnv() (*IdempotencyConfig, error) {
+	builder := NewIdempotencyConfigBuilder()
+
+	if ttlStr := os.Getenv("IDEMPOTENCY_TTL"); ttlStr != "" {
+		ttl, err := parseDuration(ttlStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IDEMPOTENCY_TTL: %w", err)
+		}
+		builder.WithTTL(ttl)
+	}
+
+	if lockTTLStr := os.Getenv("IDEMPOTENCY_LOCK_TTL"); lockTTLStr != "" {
+		lockTTL, err := parseDuration(lockTTLStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IDEMPOTENCY_LOCK_TTL: %w", err)
+		}
+		builder.WithLockTTL(lockTTL)
+	}
+
+	return builder.Build()
+}
+
+func LoadShortCodeConfigFromEnv() (*ShortCodeConfig, error) {
+	builder := NewShortCodeConfigBuilder()
+
+	if strategy := os.Getenv("SHORT_CODE_STRATEGY"); strategy != "" {
+		builder.WithStrategy(strategy)
+	}
+
+	if lengthStr := os.Getenv("SHORT_CODE_LENGTH"); lengthStr != "" {
+		length, err := strconv.Atoi(lengthStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SHORT_CODE_LENGTH: %w", err)
+		}
+		builder.WithLength(length)
+	}
+
+	if wordsStr := os.Getenv("SHORT_CODE_WORDS"); wordsStr != "" {
+		words, err := strconv.Atoi(wordsStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SHORT_CODE_WORDS: %w", err)
+		}
+		builder.WithWords(words)
+	}
+
+	if thresholdStr := os.Getenv("SHORT_CODE_GROWTH_THRESHOLD"); thresholdStr != "" {
+		threshold, err := strconv.ParseFloat(thresholdStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SHORT_CODE_GROWTH_THRESHOLD: %w", err)
+		}
+		builder.WithGrowthThreshold(threshold)
+	}
+
+	if windowStr := os.Getenv("SHORT_CODE_GROWTH_WINDOW"); windowStr != "" {
+		window, err := strconv.Atoi(windowStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SHORT_CODE_GROWTH_WINDOW: %w", err)
+		}
+		builder.WithGrowthWindow(window)
+	}
+
+	if key := os.Getenv("SHORT_CODE_SEQUENCE_KEY"); key != "" {
+		builder.WithSequenceKey(key)
+	}
+
+	if unambiguousStr := os.Getenv("SHORT_CODE_UNAMBIGUOUS"); unambiguousStr != "" {
+		unambiguous, err := strconv.ParseBool(unambiguousStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SHORT_CODE_UNAMBIGUOUS: %w", err)
+		}
+		builder.WithUnambiguous(unambiguous)
+	}
+
+	if caseInsensitiveStr := os.Getenv("SHORT_CODE_CASE_INSENSITIVE"); caseInsensitiveStr != "" {
+		caseInsensitive, err := strconv.ParseBool(caseInsensitiveStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SHORT_CODE_CASE_INSENSITIVE: %w", err)
+		}
+		builder.WithCaseInsensitive(caseInsensitive)
+	}
+
+	if path := os.Getenv("SHORT_CODE_DENYLIST_FILE"); path != "" {
+		builder.WithDenylistFile(path)
+	}
+
+	return builder.Build()
+}
+
+func LoadCodePoolConfigFromEnv() (*CodePoolConfig, error) {
+	builder := NewCodePoolConfigBuilder()
+
+	if enabledStr := os.Getenv("CODE_POOL_ENABLED"); enabledStr != "" {
+		enabled, err := strconv.ParseBool(enabledStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CODE_POOL_ENABLED: %w", err)
+		}
+		builder.WithEnabled(enabled)
+	}
+
+	if sizeStr := os.Getenv("CODE_POOL_SIZE"); sizeStr != "" {
+		size, err := strconv.Atoi(sizeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CODE_POOL_SIZE: %w", err)
+		}
+		builder.WithSize(size)
+	}
+
+	if lowWatermarkStr := os.Getenv("CODE_POOL_LOW_WATERMARK"); lowWatermarkStr != "" {
+		lowWatermark, err := strconv.Atoi(lowWatermarkStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CODE_POOL_LOW_WATERMARK: %w", err)
+		}
+		builder.WithLowWatermark(lowWatermark)
+	}
+
+	if batchSizeStr := os.Getenv("CODE_POOL_BATCH_SIZE"); batchSizeStr != "" {
+		batchSize, err := strconv.Atoi(batchSizeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CODE_POOL_BATCH_SIZE: %w", err)
+		}
+		builder.WithBatchSize(batchSize)
+	}
+
+	if intervalStr := os.Getenv("CODE_POOL_INTERVAL"); intervalStr != "" {
+		interval, err := parseDuration(intervalStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CODE_POOL_INTERVAL: %w", err)
+		}
+		builder.WithInterval(interval)
+	}
+
+	return builder.Build()
+}
+
+func LoadQRConfigFromEnv() (*QRConfig, error) {
+	builder := NewQRConfigBuilder()
+
+	if sizeStr := os.Getenv("QR_DEFAULT_SIZE"); sizeStr != "" {
+		size, err := strconv.Atoi(sizeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid QR_DEFAULT_SIZE: %w", err)
+		}
+		builder.WithDefaultSize(size)
+	}
+
+	if maxSizeStr := os.Getenv("QR_MAX_SIZE"); maxSizeStr != "" {
+		maxSize, err := strconv.Atoi(maxSizeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid QR_MAX_SIZE: %w", err)
+		}
+		builder.WithMaxSize(maxSize)
+	}
+
+	if maxAgeStr := os.Getenv("QR_CACHE_MAX_AGE"); maxAgeStr != "" {
+		maxAge, err := parseDuration(maxAgeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid QR_CACHE_MAX_AGE: %w", err)
+		}
+		builder.WithCacheMaxAge(maxAge)
+	}
+
+	return builder.Build()
+}
+
+func LoadMetadataConfigFromEnv() (*MetadataConfig, error) {
+	builder := NewMetadataConfigBuilder()
+
+	if enabledStr := os.Getenv("METADATA_ENABLED"); enabledStr != "" {
+		enabled, err := strconv.ParseBool(enabledStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid METADATA_ENABLED: %w", err)
+		}
+		builder.WithEnabled(enabled)
+	}
+
+	if timeoutStr := os.Getenv("METADATA_TIMEOUT"); timeoutStr != "" {
+		timeout, err := parseDuration(timeoutStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid METADATA_TIMEOUT: %w", err)
+		}
+		builder.WithTimeout(timeout)
+	}
+
+	if maxBodyStr := os.Getenv("METADATA_MAX_BODY_BYTES"); maxBodyStr != "" {
+		maxBody, err := strconv.ParseInt(maxBodyStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid METADATA_MAX_BODY_BYTES: %w", err)
+		}
+		builder.WithMaxBodyBytes(maxBody)
+	}
+
+	if maxRedirectsStr := os.Getenv("METADATA_MAX_REDIRECTS"); maxRedirectsStr != "" {
+		maxRedirects, err := strconv.Atoi(maxRedirectsStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid METADATA_MAX_REDIRECTS: %w", err)
+		}
+		builder.WithMaxRedirects(maxRedirects)
+	}
+
+	if userAgent := os.Getenv("METADATA_USER_AGENT"); userAgent != "" {
+		builder.WithUserAgent(userAgent)
+	}
+
+	if workersStr := os.Getenv("METADATA_WORKERS"); workersStr != "" {
+		workers, err := strconv.Atoi(workersStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid METADATA_WORKERS: %w", err)
+		}
+		builder.WithWorkers(workers)
+	}
+
+	if queueSizeStr := os.Getenv("METADATA_QUEUE_SIZE"); queueSizeStr != "" {
+		queueSize, err := strconv.Atoi(queueSizeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid METADATA_QUEUE_SIZE: %w", err)
+		}
+		builder.WithQueueSize(queueSize)
+	}
+
+	if intervalStr := os.Getenv("METADATA_SWEEP_INTERVAL"); intervalStr != "" {
+		interval, err := parseDuration(intervalStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid METADATA_SWEEP_INTERVAL: %w", err)
+		}
+		builder.WithSweepInterval(interval)
+	}
+
+	if batchSizeStr := os.Getenv("METADATA_SWEEP_BATCH_SIZE"); batchSizeStr != "" {
+		batchSize, err := strconv.Atoi(batchSizeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid METADATA_SWEEP_BATCH_SIZE: %w", err)
+		}
+		builder.WithSweepBatchSize(batchSize)
+	}
+
+	return builder.Build()
+}
+
+func LoadLinkCheckConfigFromEnv() (*LinkCheckConfig, error) {
+	builder := NewLinkCheckConfigBuilder()
+
+	if enabledStr := os.Getenv("LINK_CHECK_ENABLED"); enabledStr != "" {
+		enabled, err := strconv.ParseBool(enabledStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LINK_CHECK_ENABLED: %w", err)
+		}
+		builder.WithEnabled(enabled)
+	}
+
+	if intervalStr := os.Getenv("LINK_CHECK_INTERVAL"); intervalStr != "" {
+		interval, err := parseDuration(intervalStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LINK_CHECK_INTERVAL: %w", err)
+		}
+		builder.WithInterval(interval)
+	}
+
+	if pollIntervalStr := os.Getenv("LINK_CHECK_POLL_INTERVAL"); pollIntervalStr != "" {
+		pollInterval, err := parseDuration(pollIntervalStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LINK_CHECK_POLL_INTERVAL: %w", err)
+		}
+		builder.WithPollInterval(pollInterval)
+	}
+
+	if batchSizeStr := os.Getenv("LINK_CHECK_BATCH_SIZE"); batchSizeStr != "" {
+		batchSize, err := strconv.Atoi(batchSizeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LINK_CHECK_BATCH_SIZE: %w", err)
+		}
+		builder.WithBatchSize(batchSize)
+	}
+
+	if timeoutStr := os.Getenv("LINK_CHECK_TIMEOUT"); timeoutStr != "" {
+		timeout, err := parseDuration(timeoutStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LINK_CHECK_TIMEOUT: %w", err)
+		}
+		builder.WithTimeout(timeout)
+	}
+
+	if maxRedirectsStr := os.Getenv("LINK_CHECK_MAX_REDIRECTS"); maxRedirectsStr != "" {
+		maxRedirects, err := strconv.Atoi(maxRedirectsStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LINK_CHECK_MAX_REDIRECTS: %w", err)
+		}
+		builder.WithMaxRedirects(maxRedirects)
+	}
+
+	if concurrencyStr := os.Getenv("LINK_CHECK_CONCURRENCY"); concurrencyStr != "" {
+		concurrency, err := strconv.Atoi(concurrencyStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LINK_CHECK_CONCURRENCY: %w", err)
+		}
+		builder.WithConcurrency(concurrency)
+	}
+
+	if hostConcurrencyStr := os.Getenv("LINK_CHECK_HOST_CONCURRENCY"); hostConcurrencyStr != "" {
+		hostConcurrency, err := strconv.Atoi(hostConcurrencyStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LINK_CHECK_HOST_CONCURRENCY: %w", err)
+		}
+		builder.WithHostConcurrency(hostConcurrency)
+	}
+
+	if userAgent := os.Getenv("LINK_CHECK_USER_AGENT"); userAgent != "" {
+		builder.WithUserAgent(userAgent)
+	}
+
+	if notifyAfterStr := os.Getenv("LINK_CHECK_NOTIFY_AFTER"); notifyAfterStr != "" {
+		notifyAfter, err := strconv.Atoi(notifyAfterStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LINK_CHECK_NOTIFY_AFTER: %w", err)
+		}
+		builder.WithNotifyAfter(notifyAfter)
+	}
+
+	if disableAfterStr := os.Getenv("LINK_CHECK_DISABLE_AFTER"); disableAfterStr != "" {
+		disableAfter, err := strconv.Atoi(disableAfterStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LINK_CHECK_DISABLE_AFTER: %w", err)
+		}
+		builder.WithDisableAfter(disableAfter)
+	}
+
+	if webhookURL := os.Getenv("LINK_CHECK_WEBHOOK_URL"); webhookURL != "" {
+		builder.WithWebhook(webhookURL, os.Getenv("LINK_CHECK_WEBHOOK_SECRET"))
+	}
+
+	return builder.Build()
+}
+
+func LoadGeoIPConfigFromEnv() (*GeoIPConfig, error) {
+	builder := NewGeoIPConfigBuilder()
+
+	if path := os.Getenv("GEOIP_DATABASE_PATH"); path != "" {
+		builder.WithDatabasePath(path)
+	}
+
+	if intervalStr := os.Getenv("GEOIP_RELOAD_INTERVAL"); intervalStr != "" {
+		interval, err := parseDuration(intervalStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GEOIP_RELOAD_INTERVAL: %w", err)
+		}
+		builder.WithReloadInterval(interval)
+	}
+
+	if header := os.Getenv("GEOIP_COUNTRY_HEADER"); header != "" {
+		builder.WithCountryHeader(header)
+	}
+
+	return builder.Build()
+}
+
 // parseDuration parses duration, uses seconds as default.
 // Ex: "5s", "10", "1m", "500ms"
 func parseDuration(s string) (time.Duration, error) {
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// GeoIPConfig params for resolving visitor country used by geo targeted links.
+type GeoIPConfig struct {
+	// databasePath is MaxMind-format .mmdb file, countries aren't looked up by IP when empty
+	databasePath   string
+	reloadInterval time.Duration
+
+	// countryHeader is set by trusted edge proxy, e.g. CF-IPCountry, and takes precedence over database
+	countryHeader string
+}
+
+func (c *GeoIPConfig) DatabasePath() string {
+	return c.databasePath
+}
+
+func (c *GeoIPConfig) ReloadInterval() time.Duration {
+	return c.reloadInterval
+}
+
+func (c *GeoIPConfig) CountryHeader() string {
+	return c.countryHeader
+}
+
+// GeoIPConfigBuilder builds GeoIPConfig with validation on each step.
+type GeoIPConfigBuilder struct {
+	config GeoIPConfig
+	errors []error
+}
+
+// NewGeoIPConfigBuilder creates new builder with default values.
+func NewGeoIPConfigBuilder() *GeoIPConfigBuilder {
+	return &GeoIPConfigBuilder{
+		config: GeoIPConfig{
+			reloadInterval: 1 * time.Hour,
+		},
+		errors: make([]error, 0),
+	}
+}
+
+// WithDatabasePath sets path to country or city database.
+func (b *GeoIPConfigBuilder) WithDatabasePath(path string) *GeoIPConfigBuilder {
+	b.config.databasePath = path
+	return b
+}
+
+// WithReloadInterval sets how often database file is checked for changes, 0 reloads only on SIGHUP.
+func (b *GeoIPConfigBuilder) WithReloadInterval(interval time.Duration) *GeoIPConfigBuilder {
+	if interval < 0 {
+		b.errors = append(b.errors, fmt.Errorf("geoip reload interval can't be negative, got %v", interval))
+		return b
+	}
+	b.config.reloadInterval = interval
+	return b
+}
+
+// WithCountryHeader sets header carrying visitor country, only proxies that overwrite it may be trusted.
+func (b *GeoIPConfigBuilder) WithCountryHeader(header string) *GeoIPConfigBuilder {
+	if !isHeaderToken(header) {
+		b.errors = append(b.errors, fmt.Errorf("geoip country header must be valid header name, got %q", header))
+		return b
+	}
+	b.config.countryHeader = textproto.CanonicalMIMEHeaderKey(header)
+	return b
+}
+
+// Build creates GeoIPConfig with checking for errors.
+func (b *GeoIPConfigBuilder) Build() (*GeoIPConfig, error) {
+	if len(b.errors) > 0 {
+		return nil, fmt.Errorf("configuration errors: %v", b.errors)
+	}
+
+	return &b.config, nil
+}
+
+// isHeaderToken reports whether name is a non-empty RFC 9110 token
+func isHeaderToken(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c < 128 && strings.ContainsRune("!#$%&'*+-.^_`|~", c):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// IdempotencyConfig params for Idempotency-Key handling of create requests.
+type IdempotencyConfig struct {
+	ttl     time.Duration
+	lockTTL time.Duration
+}
+
+func (c *IdempotencyConfig) TTL() time.Duration {
+	return c.ttl
+}
+
+func (c *IdempotencyConfig) LockTTL() time.Duration {
+	return c.lockTTL
+}
+
+// IdempotencyConfigBuilder builds IdempotencyConfig with validation on each step.
+type IdempotencyConfigBuilder struct {
+	config IdempotencyConfig
+	errors []error
+}
+
+// NewIdempotencyConfigBuilder creates new builder with default values.
+func NewIdempotencyConfigBuilder() *IdempotencyConfigBuilder {
+	return &IdempotencyConfigBuilder{
+		config: IdempotencyConfig{
+			ttl:     24 * time.Hour,
+			lockTTL: 1 * time.Minute,
+		},
+		errors: make([]error, 0),
+	}
+}
+
+// WithTTL sets how long responses are replayed for repeated keys.
+func (b *IdempotencyConfigBuilder) WithTTL(ttl time.Duration) *IdempotencyConfigBuilder {
+	if ttl <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("idempotency TTL must be positive, got %v", ttl))
+		return b
+	}
+	b.config.ttl = ttl
+	return b
+}
+
+// WithLockTTL sets how long key stays reserved by a request that never finished.
+func (b *IdempotencyConfigBuilder) WithLockTTL(lockTTL time.Duration) *IdempotencyConfigBuilder {
+	if lockTTL <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("idempotency lock TTL must be positive, got %v", lockTTL))
+		return b
+	}
+	b.config.lockTTL = lockTTL
+	return b
+}
+
+// Build creates IdempotencyConfig with checking for errors.
+func (b *IdempotencyConfigBuilder) Build() (*IdempotencyConfig, error) {
+	if b.config.lockTTL > b.config.ttl {
+		b.errors = append(b.errors, fmt.Errorf("idempotency lock TTL %v can't exceed TTL %v", b.config.lockTTL, b.config.ttl))
+	}
+
+	if len(b.errors) > 0 {
+		return nil, fmt.Errorf("configuration errors: %v", b.errors)
+	}
+
+	return &b.config, nil
+}
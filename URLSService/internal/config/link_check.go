@@ -0,0 +1,240 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	maxLinkCheckBatchSize   = 10000
+	maxLinkCheckConcurrency = 256
+	maxLinkCheckRedirects   = 10
+)
+
+// LinkCheckConfig params for periodic checks of link destinations.
+type LinkCheckConfig struct {
+	enabled         bool
+	interval        time.Duration
+	pollInterval    time.Duration
+	batchSize       int
+	timeout         time.Duration
+	maxRedirects    int
+	concurrency     int
+	hostConcurrency int
+	userAgent       string
+	notifyAfter     int
+	disableAfter    int
+	webhookURL      string
+	webhookSecret   string
+}
+
+func (c *LinkCheckConfig) Enabled() bool {
+	return c.enabled
+}
+
+func (c *LinkCheckConfig) Interval() time.Duration {
+	return c.interval
+}
+
+func (c *LinkCheckConfig) PollInterval() time.Duration {
+	return c.pollInterval
+}
+
+func (c *LinkCheckConfig) BatchSize() int {
+	return c.batchSize
+}
+
+func (c *LinkCheckConfig) Timeout() time.Duration {
+	return c.timeout
+}
+
+func (c *LinkCheckConfig) MaxRedirects() int {
+	return c.maxRedirects
+}
+
+func (c *LinkCheckConfig) Concurrency() int {
+	return c.concurrency
+}
+
+func (c *LinkCheckConfig) HostConcurrency() int {
+	return c.hostConcurrency
+}
+
+func (c *LinkCheckConfig) UserAgent() string {
+	return c.userAgent
+}
+
+func (c *LinkCheckConfig) NotifyAfter() int {
+	return c.notifyAfter
+}
+
+func (c *LinkCheckConfig) DisableAfter() int {
+	return c.disableAfter
+}
+
+// WebhookURL is where owner notifications are posted, empty when owners aren't notified
+func (c *LinkCheckConfig) WebhookURL() string {
+	return c.webhookURL
+}
+
+func (c *LinkCheckConfig) WebhookSecret() string {
+	return c.webhookSecret
+}
+
+// LinkCheckConfigBuilder builds LinkCheckConfig with validation on each step.
+type LinkCheckConfigBuilder struct {
+	config LinkCheckConfig
+	errors []error
+}
+
+// NewLinkCheckConfigBuilder creates new builder with default values.
+func NewLinkCheckConfigBuilder() *LinkCheckConfigBuilder {
+	return &LinkCheckConfigBuilder{
+		config: LinkCheckConfig{
+			enabled:         false,
+			interval:        24 * time.Hour,
+			pollInterval:    5 * time.Minute,
+			batchSize:       500,
+			timeout:         10 * time.Second,
+			maxRedirects:    5,
+			concurrency:     16,
+			hostConcurrency: 2,
+			userAgent:       "URLSServiceBot/1.0 (link checker)",
+			notifyAfter:     3,
+			disableAfter:    0,
+		},
+		errors: make([]error, 0),
+	}
+}
+
+// WithEnabled turns checker on.
+func (b *LinkCheckConfigBuilder) WithEnabled(enabled bool) *LinkCheckConfigBuilder {
+	b.config.enabled = enabled
+	return b
+}
+
+// WithInterval sets how often each destination is checked.
+func (b *LinkCheckConfigBuilder) WithInterval(interval time.Duration) *LinkCheckConfigBuilder {
+	if interval <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("link check interval must be positive, got %v", interval))
+		return b
+	}
+	b.config.interval = interval
+	return b
+}
+
+// WithPollInterval sets how often links due for a check are looked up.
+func (b *LinkCheckConfigBuilder) WithPollInterval(interval time.Duration) *LinkCheckConfigBuilder {
+	if interval <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("link check poll interval must be positive, got %v", interval))
+		return b
+	}
+	b.config.pollInterval = interval
+	return b
+}
+
+// WithBatchSize sets how many links are checked per poll.
+func (b *LinkCheckConfigBuilder) WithBatchSize(batchSize int) *LinkCheckConfigBuilder {
+	if batchSize <= 0 || batchSize > maxLinkCheckBatchSize {
+		b.errors = append(b.errors, fmt.Errorf("link check batch size must be between 1 and %d, got %d", maxLinkCheckBatchSize, batchSize))
+		return b
+	}
+	b.config.batchSize = batchSize
+	return b
+}
+
+// WithTimeout sets limit for one check, redirects included.
+func (b *LinkCheckConfigBuilder) WithTimeout(timeout time.Duration) *LinkCheckConfigBuilder {
+	if timeout <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("link check timeout must be positive, got %v", timeout))
+		return b
+	}
+	b.config.timeout = timeout
+	return b
+}
+
+// WithMaxRedirects sets how many redirects are followed to reach destination.
+func (b *LinkCheckConfigBuilder) WithMaxRedirects(maxRedirects int) *LinkCheckConfigBuilder {
+	if maxRedirects < 0 || maxRedirects > maxLinkCheckRedirects {
+		b.errors = append(b.errors, fmt.Errorf("link check max redirects must be between 0 and %d, got %d", maxLinkCheckRedirects, maxRedirects))
+		return b
+	}
+	b.config.maxRedirects = maxRedirects
+	return b
+}
+
+// WithConcurrency sets how many checks run at once.
+func (b *LinkCheckConfigBuilder) WithConcurrency(concurrency int) *LinkCheckConfigBuilder {
+	if concurrency <= 0 || concurrency > maxLinkCheckConcurrency {
+		b.errors = append(b.errors, fmt.Errorf("link check concurrency must be between 1 and %d, got %d", maxLinkCheckConcurrency, concurrency))
+		return b
+	}
+	b.config.concurrency = concurrency
+	return b
+}
+
+// WithHostConcurrency sets how many checks of one host run at once.
+func (b *LinkCheckConfigBuilder) WithHostConcurrency(concurrency int) *LinkCheckConfigBuilder {
+	if concurrency <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("link check host concurrency must be positive, got %d", concurrency))
+		return b
+	}
+	b.config.hostConcurrency = concurrency
+	return b
+}
+
+// WithUserAgent sets User-Agent destinations are requested with.
+func (b *LinkCheckConfigBuilder) WithUserAgent(userAgent string) *LinkCheckConfigBuilder {
+	if userAgent == "" {
+		b.errors = append(b.errors, fmt.Errorf("link check user agent can't be empty"))
+		return b
+	}
+	b.config.userAgent = userAgent
+	return b
+}
+
+// WithNotifyAfter sets consecutive failures owner is notified at, 0 never notifies.
+func (b *LinkCheckConfigBuilder) WithNotifyAfter(failures int) *LinkCheckConfigBuilder {
+	if failures < 0 {
+		b.errors = append(b.errors, fmt.Errorf("link check notify after can't be negative, got %d", failures))
+		return b
+	}
+	b.config.notifyAfter = failures
+	return b
+}
+
+// WithDisableAfter sets consecutive failures link is turned off at, 0 never disables.
+func (b *LinkCheckConfigBuilder) WithDisableAfter(failures int) *LinkCheckConfigBuilder {
+	if failures < 0 {
+		b.errors = append(b.errors, fmt.Errorf("link check disable after can't be negative, got %d", failures))
+		return b
+	}
+	b.config.disableAfter = failures
+	return b
+}
+
+// WithWebhook sets where owner notifications are posted and secret they are signed with.
+func (b *LinkCheckConfigBuilder) WithWebhook(webhookURL string, secret string) *LinkCheckConfigBuilder {
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		b.errors = append(b.errors, fmt.Errorf("link check webhook must be absolute http(s) URL, got %q", webhookURL))
+		return b
+	}
+	b.config.webhookURL = webhookURL
+	b.config.webhookSecret = secret
+	return b
+}
+
+// Build creates LinkCheckConfig with checking for errors.
+func (b *LinkCheckConfigBuilder) Build() (*LinkCheckConfig, error) {
+	if b.config.pollInterval > b.config.interval {
+		b.errors = append(b.errors, fmt.Errorf("link check poll interval %v must not exceed interval %v", b.config.pollInterval, b.config.interval))
+	}
+
+	if len(b.errors) > 0 {
+		return nil, fmt.Errorf("configuration errors: %v", b.errors)
+	}
+
+	return &b.config, nil
+}
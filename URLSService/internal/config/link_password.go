@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// bcrypt cost bounds, see golang.org/x/crypto/bcrypt MinCost and MaxCost
+const (
+	minBcryptCost = 4
+	maxBcryptCost = 31
+)
+
+// LinkPasswordConfig params for password-protected links.
+type LinkPasswordConfig struct {
+	maxAttempts   int64
+	attemptWindow time.Duration
+	bcryptCost    int
+}
+
+func (c *LinkPasswordConfig) MaxAttempts() int64 {
+	return c.maxAttempts
+}
+
+func (c *LinkPasswordConfig) AttemptWindow() time.Duration {
+	return c.attemptWindow
+}
+
+func (c *LinkPasswordConfig) BcryptCost() int {
+	return c.bcryptCost
+}
+
+// LinkPasswordConfigBuilder builds LinkPasswordConfig with validation on each step.
+type LinkPasswordConfigBuilder struct {
+	config LinkPasswordConfig
+	errors []error
+}
+
+// NewLinkPasswordConfigBuilder creates new builder with default values.
+func NewLinkPasswordConfigBuilder() *LinkPasswordConfigBuilder {
+	return &LinkPasswordConfigBuilder{
+		config: LinkPasswordConfig{
+			maxAttempts:   10,
+			attemptWindow: 15 * time.Minute,
+			bcryptCost:    10,
+		},
+		errors: make([]error, 0),
+	}
+}
+
+// WithMaxAttempts sets password attempts allowed per link within window, a correct one resets the count.
+func (b *LinkPasswordConfigBuilder) WithMaxAttempts(maxAttempts int64) *LinkPasswordConfigBuilder {
+	if maxAttempts <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("max password attempts must be positive, got %d", maxAttempts))
+		return b
+	}
+	b.config.maxAttempts = maxAttempts
+	return b
+}
+
+// WithAttemptWindow sets how long failed attempts are counted and link stays locked.
+func (b *LinkPasswordConfigBuilder) WithAttemptWindow(window time.Duration) *LinkPasswordConfigBuilder {
+	if window <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("password attempt window must be positive, got %v", window))
+		return b
+	}
+	b.config.attemptWindow = window
+	return b
+}
+
+// WithBcryptCost sets bcrypt cost used for new passwords.
+func (b *LinkPasswordConfigBuilder) WithBcryptCost(cost int) *LinkPasswordConfigBuilder {
+	if cost < minBcryptCost || cost > maxBcryptCost {
+		b.errors = append(b.errors, fmt.Errorf("bcrypt cost must be between %d and %d, got %d", minBcryptCost, maxBcryptCost, cost))
+		return b
+	}
+	b.config.bcryptCost = cost
+	return b
+}
+
+// Build creates LinkPasswordConfig with checking for errors.
+func (b *LinkPasswordConfigBuilder) Build() (*LinkPasswordConfig, error) {
+	if len(b.errors) > 0 {
+		return nil, fmt.Errorf("configuration errors: %v", b.errors)
+	}
+
+	return &b.config, nil
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	maxMetadataBodyBytes = 10 << 20
+	maxMetadataRedirects = 10
+	maxMetadataWorkers   = 64
+)
+
+// MetadataConfig params for background fetching of destination page details.
+type MetadataConfig struct {
+	enabled        bool
+	timeout        time.Duration
+	maxBodyBytes   int64
+	maxRedirects   int
+	userAgent      string
+	workers        int
+	queueSize      int
+	sweepInterval  time.Duration
+	sweepBatchSize int
+}
+
+func (c *MetadataConfig) Enabled() bool {
+	return c.enabled
+}
+
+func (c *MetadataConfig) Timeout() time.Duration {
+	return c.timeout
+}
+
+func (c *MetadataConfig) MaxBodyBytes() int64 {
+	return c.maxBodyBytes
+}
+
+func (c *MetadataConfig) MaxRedirects() int {
+	return c.maxRedirects
+}
+
+func (c *MetadataConfig) UserAgent() string {
+	return c.userAgent
+}
+
+func (c *MetadataConfig) Workers() int {
+	return c.workers
+}
+
+func (c *MetadataConfig) QueueSize() int {
+	return c.queueSize
+}
+
+func (c *MetadataConfig) SweepInterval() time.Duration {
+	return c.sweepInterval
+}
+
+func (c *MetadataConfig) SweepBatchSize() int {
+	return c.sweepBatchSize
+}
+
+// MetadataConfigBuilder builds MetadataConfig with validation on each step.
+type MetadataConfigBuilder struct {
+	config MetadataConfig
+	errors []error
+}
+
+// NewMetadataConfigBuilder creates new builder with default values.
+func NewMetadataConfigBuilder() *MetadataConfigBuilder {
+	return &MetadataConfigBuilder{
+		config: MetadataConfig{
+			enabled:        false,
+			timeout:        5 * time.Second,
+			maxBodyBytes:   512 << 10,
+			maxRedirects:   3,
+			userAgent:      "URLSServiceBot/1.0 (link preview)",
+			workers:        4,
+			queueSize:      1000,
+			sweepInterval:  time.Minute,
+			sweepBatchSize: 100,
+		},
+		errors: make([]error, 0),
+	}
+}
+
+// WithEnabled turns fetching on, links are listed without page details otherwise.
+func (b *MetadataConfigBuilder) WithEnabled(enabled bool) *MetadataConfigBuilder {
+	b.config.enabled = enabled
+	return b
+}
+
+// WithTimeout sets limit for whole page download, redirects included.
+func (b *MetadataConfigBuilder) WithTimeout(timeout time.Duration) *MetadataConfigBuilder {
+	if timeout <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("metadata timeout must be positive, got %v", timeout))
+		return b
+	}
+	b.config.timeout = timeout
+	return b
+}
+
+// WithMaxBodyBytes sets how much of a page is downloaded and parsed.
+func (b *MetadataConfigBuilder) WithMaxBodyBytes(maxBodyBytes int64) *MetadataConfigBuilder {
+	if maxBodyBytes < 1024 || maxBodyBytes > maxMetadataBodyBytes {
+		b.errors = append(b.errors, fmt.Errorf("metadata max body bytes must be between 1024 and %d, got %d", maxMetadataBodyBytes, maxBodyBytes))
+		return b
+	}
+	b.config.maxBodyBytes = maxBodyBytes
+	return b
+}
+
+// WithMaxRedirects sets how many redirects are followed to reach the page.
+func (b *MetadataConfigBuilder) WithMaxRedirects(maxRedirects int) *MetadataConfigBuilder {
+	if maxRedirects < 0 || maxRedirects > maxMetadataRedirects {
+		b.errors = append(b.errors, fmt.Errorf("metadata max redirects must be between 0 and %d, got %d", maxMetadataRedirects, maxRedirects))
+		return b
+	}
+	b.config.maxRedirects = maxRedirects
+	return b
+}
+
+// WithUserAgent sets User-Agent pages are requested with.
+func (b *MetadataConfigBuilder) WithUserAgent(userAgent string) *MetadataConfigBuilder {
+	if userAgent == "" {
+		b.errors = append(b.errors, fmt.Errorf("metadata user agent can't be empty"))
+		return b
+	}
+	b.config.userAgent = userAgent
+	return b
+}
+
+// WithWorkers sets how many pages are fetched concurrently.
+func (b *MetadataConfigBuilder) WithWorkers(workers int) *MetadataConfigBuilder {
+	if workers <= 0 || workers > maxMetadataWorkers {
+		b.errors = append(b.errors, fmt.Errorf("metadata workers must be between 1 and %d, got %d", maxMetadataWorkers, workers))
+		return b
+	}
+	b.config.workers = workers
+	return b
+}
+
+// WithQueueSize sets how many new links wait for a worker before they are left to sweep.
+func (b *MetadataConfigBuilder) WithQueueSize(queueSize int) *MetadataConfigBuilder {
+	if queueSize <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("metadata queue size must be positive, got %d", queueSize))
+		return b
+	}
+	b.config.queueSize = queueSize
+	return b
+}
+
+// WithSweepInterval sets how often links missing metadata are looked up.
+func (b *MetadataConfigBuilder) WithSweepInterval(interval time.Duration) *MetadataConfigBuilder {
+	if interval <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("metadata sweep interval must be positive, got %v", interval))
+		return b
+	}
+	b.config.sweepInterval = interval
+	return b
+}
+
+// WithSweepBatchSize sets how many links one sweep queues.
+func (b *MetadataConfigBuilder) WithSweepBatchSize(batchSize int) *MetadataConfigBuilder {
+	if batchSize <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("metadata sweep batch size must be positive, got %d", batchSize))
+		return b
+	}
+	b.config.sweepBatchSize = batchSize
+	return b
+}
+
+// Build creates MetadataConfig with checking for errors.
+func (b *MetadataConfigBuilder) Build() (*MetadataConfig, error) {
+	if len(b.errors) > 0 {
+		return nil, fmt.Errorf("configuration errors: %v", b.errors)
+	}
+
+	return &b.config, nil
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxPurgeBatchSize keeps a single delete statement short enough not to hold row locks for long
+const maxPurgeBatchSize = 10000
+
+// PurgeConfig params for background removal of expired and trashed URLs.
+type PurgeConfig struct {
+	enabled        bool
+	interval       time.Duration
+	batchSize      int
+	batchPause     time.Duration
+	retention      time.Duration
+	trashRetention time.Duration
+}
+
+func (c *PurgeConfig) Enabled() bool {
+	return c.enabled
+}
+
+func (c *PurgeConfig) Interval() time.Duration {
+	return c.interval
+}
+
+func (c *PurgeConfig) BatchSize() int {
+	return c.batchSize
+}
+
+func (c *PurgeConfig) BatchPause() time.Duration {
+	return c.batchPause
+}
+
+func (c *PurgeConfig) Retention() time.Duration {
+	return c.retention
+}
+
+func (c *PurgeConfig) TrashRetention() time.Duration {
+	return c.trashRetention
+}
+
+// PurgeConfigBuilder builds PurgeConfig with validation on each step.
+type PurgeConfigBuilder struct {
+	config PurgeConfig
+	errors []error
+}
+
+// NewPurgeConfigBuilder creates new builder with default values.
+func NewPurgeConfigBuilder() *PurgeConfigBuilder {
+	return &PurgeConfigBuilder{
+		config: PurgeConfig{
+			enabled:        true,
+			interval:       1 * time.Hour,
+			batchSize:      1000,
+			batchPause:     100 * time.Millisecond,
+			retention:      30 * 24 * time.Hour,
+			trashRetention: 30 * 24 * time.Hour,
+		},
+		errors: make([]error, 0),
+	}
+}
+
+// WithEnabled turns purge worker on or off.
+func (b *PurgeConfigBuilder) WithEnabled(enabled bool) *PurgeConfigBuilder {
+	b.config.enabled = enabled
+	return b
+}
+
+// WithInterval sets how often expired URLs are looked for.
+func (b *PurgeConfigBuilder) WithInterval(interval time.Duration) *PurgeConfigBuilder {
+	if interval <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("purge interval must be positive, got %v", interval))
+		return b
+	}
+	b.config.interval = interval
+	return b
+}
+
+// WithBatchSize sets how many URLs a single delete statement removes.
+func (b *PurgeConfigBuilder) WithBatchSize(batchSize int) *PurgeConfigBuilder {
+	if batchSize <= 0 || batchSize > maxPurgeBatchSize {
+		b.errors = append(b.errors, fmt.Errorf("purge batch size must be between 1 and %d, got %d", maxPurgeBatchSize, batchSize))
+		return b
+	}
+	b.config.batchSize = batchSize
+	return b
+}
+
+// WithBatchPause sets delay between batches, so purge doesn't saturate the database.
+func (b *PurgeConfigBuilder) WithBatchPause(pause time.Duration) *PurgeConfigBuilder {
+	if pause < 0 {
+		b.errors = append(b.errors, fmt.Errorf("purge batch pause can't be negative, got %v", pause))
+		return b
+	}
+	b.config.batchPause = pause
+	return b
+}
+
+// WithRetention sets how long expired URLs are kept, owners can still list them meanwhile.
+func (b *PurgeConfigBuilder) WithRetention(retention time.Duration) *PurgeConfigBuilder {
+	if retention < 0 {
+		b.errors = append(b.errors, fmt.Errorf("purge retention can't be negative, got %v", retention))
+		return b
+	}
+	b.config.retention = retention
+	return b
+}
+
+// WithTrashRetention sets how long deleted URLs stay in trash and keep their short codes.
+func (b *PurgeConfigBuilder) WithTrashRetention(retention time.Duration) *PurgeConfigBuilder {
+	if retention < 0 {
+		b.errors = append(b.errors, fmt.Errorf("trash retention can't be negative, got %v", retention))
+		return b
+	}
+	b.config.trashRetention = retention
+	return b
+}
+
+// Build creates PurgeConfig with checking for errors.
+func (b *PurgeConfigBuilder) Build() (*PurgeConfig, error) {
+	if len(b.errors) > 0 {
+		return nil, fmt.Errorf("configuration errors: %v", b.errors)
+	}
+
+	return &b.config, nil
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// minQRSize is the smallest image side still scannable from print
+const minQRSize = 64
+
+// QRConfig params for QR codes of short links.
+type QRConfig struct {
+	defaultSize int
+	maxSize     int
+	cacheMaxAge time.Duration
+}
+
+func (c *QRConfig) DefaultSize() int {
+	return c.defaultSize
+}
+
+func (c *QRConfig) MaxSize() int {
+	return c.maxSize
+}
+
+func (c *QRConfig) CacheMaxAge() time.Duration {
+	return c.cacheMaxAge
+}
+
+// QRConfigBuilder builds QRConfig with validation on each step.
+type QRConfigBuilder struct {
+	config QRConfig
+	errors []error
+}
+
+// NewQRConfigBuilder creates new builder with default values.
+func NewQRConfigBuilder() *QRConfigBuilder {
+	return &QRConfigBuilder{
+		config: QRConfig{
+			defaultSize: 256,
+			maxSize:     2048,
+			cacheMaxAge: 24 * time.Hour,
+		},
+		errors: make([]error, 0),
+	}
+}
+
+// WithDefaultSize sets image side in pixels used when request has none.
+func (b *QRConfigBuilder) WithDefaultSize(size int) *QRConfigBuilder {
+	if size < minQRSize {
+		b.errors = append(b.errors, fmt.Errorf("QR default size must be at least %d, got %d", minQRSize, size))
+		return b
+	}
+	b.config.defaultSize = size
+	return b
+}
+
+// WithMaxSize sets largest image side in pixels clients may request.
+func (b *QRConfigBuilder) WithMaxSize(size int) *QRConfigBuilder {
+	if size < minQRSize {
+		b.errors = append(b.errors, fmt.Errorf("QR max size must be at least %d, got %d", minQRSize, size))
+		return b
+	}
+	b.config.maxSize = size
+	return b
+}
+
+// WithCacheMaxAge sets upper bound for QR Cache-Control max-age, which otherwise follows link expiration.
+func (b *QRConfigBuilder) WithCacheMaxAge(maxAge time.Duration) *QRConfigBuilder {
+	if maxAge < 0 {
+		b.errors = append(b.errors, fmt.Errorf("QR cache max age cannot be negative, got %v", maxAge))
+		return b
+	}
+	b.config.cacheMaxAge = maxAge
+	return b
+}
+
+// Build creates QRConfig with checking for errors.
+func (b *QRConfigBuilder) Build() (*QRConfig, error) {
+	if b.config.defaultSize > b.config.maxSize {
+		b.errors = append(b.errors, fmt.Errorf("QR default size %d can't exceed max size %d", b.config.defaultSize, b.config.maxSize))
+	}
+
+	if len(b.errors) > 0 {
+		return nil, fmt.Errorf("configuration errors: %v", b.errors)
+	}
+
+	return &b.config, nil
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RedirectConfig params for the public short link redirect endpoint.
+type RedirectConfig struct {
+	// defaultStatus is used for links created without an explicit redirect type
+	defaultStatus int
+
+	// cacheMaxAge caps Cache-Control max-age, which otherwise follows link expiration
+	cacheMaxAge time.Duration
+
+	// publicBaseURL is origin short links are served from, request host is used when empty
+	publicBaseURL string
+}
+
+func (c *RedirectConfig) DefaultStatus() int {
+	return c.defaultStatus
+}
+
+func (c *RedirectConfig) CacheMaxAge() time.Duration {
+	return c.cacheMaxAge
+}
+
+func (c *RedirectConfig) PublicBaseURL() string {
+	return c.publicBaseURL
+}
+
+// RedirectConfigBuilder builds RedirectConfig with validation on each step.
+type RedirectConfigBuilder struct {
+	config RedirectConfig
+	errors []error
+}
+
+// NewRedirectConfigBuilder creates new builder with default values.
+func NewRedirectConfigBuilder() *RedirectConfigBuilder {
+	return &RedirectConfigBuilder{
+		config: RedirectConfig{
+			defaultStatus: http.StatusFound,
+			cacheMaxAge:   1 * time.Hour,
+		},
+		errors: make([]error, 0),
+	}
+}
+
+// WithDefaultStatus sets redirect status used when link has none.
+func (b *RedirectConfigBuilder) WithDefaultStatus(status int) *RedirectConfigBuilder {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		b.config.defaultStatus = status
+	default:
+		b.errors = append(b.errors, fmt.Errorf("invalid redirect status: %d (valid: 301, 302, 307, 308)", status))
+	}
+	return b
+}
+
+// WithCacheMaxAge sets upper bound for redirect Cache-Control max-age.
+func (b *RedirectConfigBuilder) WithCacheMaxAge(maxAge time.Duration) *RedirectConfigBuilder {
+	if maxAge < 0 {
+		b.errors = append(b.errors, fmt.Errorf("cache max age cannot be negative, got %v", maxAge))
+		return b
+	}
+	b.config.cacheMaxAge = maxAge
+	return b
+}
+
+// WithPublicBaseURL sets origin of public short links, e.g. https://sho.rt
+func (b *RedirectConfigBuilder) WithPublicBaseURL(baseURL string) *RedirectConfigBuilder {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" ||
+		parsed.RawQuery != "" || parsed.Fragment != "" {
+		b.errors = append(b.errors, fmt.Errorf("public base URL must be absolute http(s) URL without query, got %q", baseURL))
+		return b
+	}
+	b.config.publicBaseURL = strings.TrimSuffix(baseURL, "/")
+	return b
+}
+
+// Build creates RedirectConfig with checking for errors.
+func (b *RedirectConfigBuilder) Build() (*RedirectConfig, error) {
+	if len(b.errors) > 0 {
+		return nil, fmt.Errorf("configuration errors: %v", b.errors)
+	}
+
+	return &b.config, nil
+}
@@ -0,0 +1,177 @@
+package config
+
+import "fmt"
+
+// Short code generation strategies
+const (
+	ShortCodeStrategyBase62   = "base62"
+	ShortCodeStrategySequence = "sequence"
+	ShortCodeStrategyWordlist = "wordlist"
+)
+
+// Limits for code growth, codes stay well below short_code column width
+const (
+	MaxShortCodeLength = 16
+	MaxShortCodeWords  = 6
+)
+
+// ShortCodeConfig params for generated short codes.
+type ShortCodeConfig struct {
+	strategy        string
+	length          int
+	words           int
+	growthThreshold float64
+	growthWindow    int
+	sequenceKey     string
+	unambiguous     bool
+	caseInsensitive bool
+	denylistFile    string
+}
+
+func (c *ShortCodeConfig) Strategy() string {
+	return c.strategy
+}
+
+func (c *ShortCodeConfig) Length() int {
+	return c.length
+}
+
+func (c *ShortCodeConfig) Words() int {
+	return c.words
+}
+
+func (c *ShortCodeConfig) GrowthThreshold() float64 {
+	return c.growthThreshold
+}
+
+func (c *ShortCodeConfig) GrowthWindow() int {
+	return c.growthWindow
+}
+
+func (c *ShortCodeConfig) SequenceKey() string {
+	return c.sequenceKey
+}
+
+func (c *ShortCodeConfig) Unambiguous() bool {
+	return c.unambiguous
+}
+
+func (c *ShortCodeConfig) CaseInsensitive() bool {
+	return c.caseInsensitive
+}
+
+func (c *ShortCodeConfig) DenylistFile() string {
+	return c.denylistFile
+}
+
+// ShortCodeConfigBuilder builds ShortCodeConfig with validation on each step.
+type ShortCodeConfigBuilder struct {
+	config ShortCodeConfig
+	errors []error
+}
+
+// NewShortCodeConfigBuilder creates new builder with default values.
+func NewShortCodeConfigBuilder() *ShortCodeConfigBuilder {
+	return &ShortCodeConfigBuilder{
+		config: ShortCodeConfig{
+			strategy:        ShortCodeStrategyBase62,
+			length:          7,
+			words:           3,
+			growthThreshold: 0.01,
+			growthWindow:    1000,
+		},
+		errors: make([]error, 0),
+	}
+}
+
+// WithStrategy sets how codes are generated: base62, sequence or wordlist.
+func (b *ShortCodeConfigBuilder) WithStrategy(strategy string) *ShortCodeConfigBuilder {
+	switch strategy {
+	case ShortCodeStrategyBase62, ShortCodeStrategySequence, ShortCodeStrategyWordlist:
+		b.config.strategy = strategy
+	default:
+		b.errors = append(b.errors, fmt.Errorf("short code strategy must be one of base62, sequence, wordlist, got %q", strategy))
+	}
+	return b
+}
+
+// WithLength sets initial length of base62 codes.
+func (b *ShortCodeConfigBuilder) WithLength(length int) *ShortCodeConfigBuilder {
+	if length < 4 || length > MaxShortCodeLength {
+		b.errors = append(b.errors, fmt.Errorf("short code length must be between 4 and %d, got %d", MaxShortCodeLength, length))
+		return b
+	}
+	b.config.length = length
+	return b
+}
+
+// WithWords sets initial number of words in wordlist codes.
+func (b *ShortCodeConfigBuilder) WithWords(words int) *ShortCodeConfigBuilder {
+	if words < 2 || words > MaxShortCodeWords {
+		b.errors = append(b.errors, fmt.Errorf("short code words must be between 2 and %d, got %d", MaxShortCodeWords, words))
+		return b
+	}
+	b.config.words = words
+	return b
+}
+
+// WithGrowthThreshold sets collision rate above which random codes get longer.
+func (b *ShortCodeConfigBuilder) WithGrowthThreshold(threshold float64) *ShortCodeConfigBuilder {
+	if threshold <= 0 || threshold >= 1 {
+		b.errors = append(b.errors, fmt.Errorf("short code growth threshold must be between 0 and 1, got %v", threshold))
+		return b
+	}
+	b.config.growthThreshold = threshold
+	return b
+}
+
+// WithGrowthWindow sets how many generated codes the collision rate is measured over.
+func (b *ShortCodeConfigBuilder) WithGrowthWindow(window int) *ShortCodeConfigBuilder {
+	if window <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("short code growth window must be positive, got %d", window))
+		return b
+	}
+	b.config.growthWindow = window
+	return b
+}
+
+// WithSequenceKey sets secret that scrambles sequence codes.
+func (b *ShortCodeConfigBuilder) WithSequenceKey(key string) *ShortCodeConfigBuilder {
+	b.config.sequenceKey = key
+	return b
+}
+
+// WithUnambiguous switches base62 and sequence codes to lowercase alphabet without look-alikes.
+func (b *ShortCodeConfigBuilder) WithUnambiguous(unambiguous bool) *ShortCodeConfigBuilder {
+	b.config.unambiguous = unambiguous
+	return b
+}
+
+// WithCaseInsensitive makes lookups match generated codes in any case.
+func (b *ShortCodeConfigBuilder) WithCaseInsensitive(caseInsensitive bool) *ShortCodeConfigBuilder {
+	b.config.caseInsensitive = caseInsensitive
+	return b
+}
+
+// WithDenylistFile sets file of substrings generated codes must not contain.
+func (b *ShortCodeConfigBuilder) WithDenylistFile(path string) *ShortCodeConfigBuilder {
+	b.config.denylistFile = path
+	return b
+}
+
+// Build creates ShortCodeConfig with checking for errors.
+func (b *ShortCodeConfigBuilder) Build() (*ShortCodeConfig, error) {
+	if b.config.strategy == ShortCodeStrategySequence && b.config.sequenceKey == "" {
+		b.errors = append(b.errors, fmt.Errorf("short code sequence key is required for sequence strategy"))
+	}
+	// Lowercased mixed case code may belong to another link
+	if b.config.caseInsensitive && !b.config.unambiguous && b.config.strategy != ShortCodeStrategyWordlist {
+		b.errors = append(b.errors, fmt.Errorf("case-insensitive short codes require unambiguous alphabet or wordlist strategy"))
+	}
+
+	if len(b.errors) > 0 {
+		return nil, fmt.Errorf("configuration errors: %v", b.errors)
+	}
+
+	return &b.config, nil
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+)
+
+// URLValidationConfig params for destination URL validation and normalization.
+type URLValidationConfig struct {
+	maxLength         int
+	stripFragment     bool
+	allowPrivateHosts bool
+}
+
+func (c *URLValidationConfig) MaxLength() int {
+	return c.maxLength
+}
+
+func (c *URLValidationConfig) StripFragment() bool {
+	return c.stripFragment
+}
+
+func (c *URLValidationConfig) AllowPrivateHosts() bool {
+	return c.allowPrivateHosts
+}
+
+// URLValidationConfigBuilder builds URLValidationConfig with validation on each step.
+type URLValidationConfigBuilder struct {
+	config URLValidationConfig
+	errors []error
+}
+
+// NewURLValidationConfigBuilder creates new builder with default values.
+func NewURLValidationConfigBuilder() *URLValidationConfigBuilder {
+	return &URLValidationConfigBuilder{
+		config: URLValidationConfig{
+			maxLength:         2048,
+			stripFragment:     false,
+			allowPrivateHosts: false,
+		},
+		errors: make([]error, 0),
+	}
+}
+
+// WithMaxLength sets max length of destination URL.
+func (b *URLValidationConfigBuilder) WithMaxLength(maxLength int) *URLValidationConfigBuilder {
+	if maxLength <= 0 {
+		b.errors = append(b.errors, fmt.Errorf("max URL length must be positive, got %d", maxLength))
+		return b
+	}
+	b.config.maxLength = maxLength
+	return b
+}
+
+// WithStripFragment sets whether #fragment is removed from destination URL.
+func (b *URLValidationConfigBuilder) WithStripFragment(strip bool) *URLValidationConfigBuilder {
+	b.config.stripFragment = strip
+	return b
+}
+
+// WithAllowPrivateHosts allows private, loopback and link-local destinations (dev environments).
+func (b *URLValidationConfigBuilder) WithAllowPrivateHosts(allow bool) *URLValidationConfigBuilder {
+	b.config.allowPrivateHosts = allow
+	return b
+}
+
+// Build creates URLValidationConfig with checking for errors.
+func (b *URLValidationConfigBuilder) Build() (*URLValidationConfig, error) {
+	if len(b.errors) > 0 {
+		return nil, fmt.Errorf("configuration errors: %v", b.errors)
+	}
+
+	return &b.config, nil
+}
@@ -0,0 +1,15 @@
+package domain
+
+type BlockRuleKind string
+
+const (
+	BlockRuleDomain   BlockRuleKind = "domain"   // exact host
+	BlockRuleWildcard BlockRuleKind = "wildcard" // any subdomain of pattern
+	BlockRuleRegex    BlockRuleKind = "regex"    // matched against host + path
+)
+
+type BlockRule struct {
+	Kind    BlockRuleKind
+	Pattern string
+	Reason  string
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"net/netip"
+	"time"
+)
+
+// Visitor describes client resolving a short link
+type Visitor struct {
+	IP        netip.Addr
+	UserAgent string
+	Referrer  string
+	UserID    *string
+	Country   string // reported by trusted proxy header, empty when not configured
+}
+
+type Click struct {
+	ShortCode string
+	ClickedAt time.Time
+	Referrer  string
+	UserAgent string
+	IP        netip.Addr // anonymized, zero value when unknown
+	UserID    *string
+}
+
+type ClickBucket string
+
+const (
+	ClickBucketHour ClickBucket = "hour"
+	ClickBucketDay  ClickBucket = "day"
+	ClickBucketWeek ClickBucket = "week"
+)
+
+type ClickSeriesPoint struct {
+	Start time.Time
+	Count int64
+}
+
+type ClickCount struct {
+	Value string
+	Count int64
+}
+
+type ClickStats struct {
+	ShortCode     string
+	Total         int64
+	Bucket        ClickBucket
+	From          time.Time
+	To            time.Time
+	Series        []ClickSeriesPoint
+	TopReferrers  []ClickCount
+	TopUserAgents []ClickCount
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// LinkHealth is outcome of the latest destination check of a link
+type LinkHealth struct {
+	SourceURL           string // destination that was checked
+	StatusCode          int    // 0 when destination didn't respond
+	Error               string // why destination didn't respond
+	ConsecutiveFailures int    // 0 means destination is healthy
+	CheckedAt           time.Time
+}
+
+func (h *LinkHealth) Healthy() bool {
+	return h.ConsecutiveFailures == 0
+}
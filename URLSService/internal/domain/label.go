@@ -0,0 +1,15 @@
+package domain
+
+// LabelKind tells tags, many per link, from collections, one per link
+type LabelKind string
+
+const (
+	LabelTag        LabelKind = "tag"
+	LabelCollection LabelKind = "collection"
+)
+
+// Label is user's tag or collection with number of links carrying it
+type Label struct {
+	Name     string
+	URLCount int
+}
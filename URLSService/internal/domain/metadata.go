@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// LinkMetadata describes destination page, it is fetched in background after link is created
+type LinkMetadata struct {
+	SourceURL   string // destination the page was fetched from
+	Title       string
+	Description string
+	ImageURL    string
+	SiteName    string
+	FaviconURL  string
+	FetchError  string // set when page couldn't be fetched, other fields are empty then
+	FetchedAt   time.Time
+}
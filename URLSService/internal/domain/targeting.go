@@ -0,0 +1,51 @@
+package domain
+
+import "slices"
+
+// ClientOS is operating system of visitor's client
+type ClientOS string
+
+const (
+	ClientOSIOS      ClientOS = "ios"
+	ClientOSAndroid  ClientOS = "android"
+	ClientOSWindows  ClientOS = "windows"
+	ClientOSMacOS    ClientOS = "macos"
+	ClientOSLinux    ClientOS = "linux"
+	ClientOSChromeOS ClientOS = "chromeos"
+)
+
+// DeviceClass is form factor of visitor's client
+type DeviceClass string
+
+const (
+	DeviceMobile  DeviceClass = "mobile"
+	DeviceTablet  DeviceClass = "tablet"
+	DeviceDesktop DeviceClass = "desktop"
+)
+
+// ClientInfo is what targeting rules know about visitor, OS and Country are empty when unknown
+type ClientInfo struct {
+	OS      ClientOS
+	Device  DeviceClass
+	Bot     bool
+	Country string // ISO 3166-1 alpha-2, e.g. DE
+}
+
+// TargetRule sends visitors matching all of its set conditions to URL.
+// Rules of a link are tried in order, link's OriginalURL is the default destination.
+type TargetRule struct {
+	OS     ClientOS    `json:"os,omitempty"`
+	Device DeviceClass `json:"device,omitempty"`
+	Bot    *bool       `json:"bot,omitempty"`
+	// Countries match visitor in any of them, visitors of unknown country never match
+	Countries []string `json:"countries,omitempty"`
+	URL       string   `json:"url"`
+}
+
+// Matches reports whether client meets every condition of the rule
+func (r TargetRule) Matches(client ClientInfo) bool {
+	return (r.OS == "" || r.OS == client.OS) &&
+		(r.Device == "" || r.Device == client.Device) &&
+		(r.Bot == nil || *r.Bot == client.Bot) &&
+		(len(r.Countries) == 0 || slices.Contains(r.Countries, client.Country))
+}
@@ -3,9 +3,25 @@ package domain
 import "time"
 
 type URL struct {
-	ShortCode   string
-	OriginalURL string
-	UserID      *string
-	ExpiresAt   time.Time
-	CreatedAt   time.Time
+	ShortCode    string
+	OriginalURL  string
+	UserID       *string
+	RedirectType int    // 0 means service default
+	PasswordHash string // bcrypt hash, empty for public links; plaintext is never kept
+	MaxClicks    int    // 0 means unlimited
+	ClicksUsed   int
+	Disabled     bool // turned off by owner, zero value keeps URLs cached before the flag existed active
+	Version      int  // bumped on every edit
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+	DeletedAt    *time.Time   // set while link is in owner's trash
+	Title        string       // owner's description shown on preview page
+	Interstitial bool         // visitors confirm on a warning page before redirect
+	Targets      []TargetRule // checked in order before falling back to OriginalURL
+
+	// Owner's labels and destination details, loaded only for owner listings and never cached
+	Tags       []string      `json:"-"`
+	Collection string        `json:"-"`
+	Metadata   *LinkMetadata `json:"-"` // nil until fetched or when destination changed since
+	Health     *LinkHealth   `json:"-"` // nil until checked or when destination changed since
 }
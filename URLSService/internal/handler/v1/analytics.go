@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/service"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type AnalyticsHandler struct {
+	service service.AnalyticsService
+}
+
+func NewAnalyticsHandler(service service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{service: service}
+}
+
+// Stats returns click statistics for a short URL owned by the current user
+func (h *AnalyticsHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	shortCode := chi.URLParam(r, "shortCode")
+
+	// Get user ID from X-User-Id header (set by Traefik ForwardAuth)
+	userID := r.Header.Get("X-User-Id")
+	if userID == "" {
+		logger.AppLogInfoCtx(ctx, "No user ID provided for stats operation")
+		respondWithError(ctx, w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	// Parse query params
+	query := r.URL.Query()
+	bucket := domain.ClickBucket(query.Get("bucket"))
+	var from, to time.Time
+	if f := query.Get("from"); f != "" {
+		parsed, err := time.Parse(time.RFC3339, f)
+		if err != nil {
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid from", "expected RFC3339 timestamp")
+			return
+		}
+		from = parsed
+	}
+	if t := query.Get("to"); t != "" {
+		parsed, err := time.Parse(time.RFC3339, t)
+		if err != nil {
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid to", "expected RFC3339 timestamp")
+			return
+		}
+		to = parsed
+	}
+
+	stats, err := h.service.GetStats(ctx, shortCode, userID, bucket, from, to)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidBucket):
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid bucket", err.Error())
+		case errors.Is(err, service.ErrInvalidRange):
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid time range", err.Error())
+		case errors.Is(err, service.ErrNotFound):
+			logger.AppLogInfoCtx(ctx, "URL not found",
+				zap.String("short_code", shortCode),
+			)
+			respondWithError(ctx, w, http.StatusNotFound, "URL not found", "")
+		case errors.Is(err, service.ErrForbidden):
+			logger.AppLogInfoCtx(ctx, "Access denied for URL stats",
+				zap.String("short_code", shortCode),
+				zap.String("user_id", userID),
+			)
+			respondWithError(ctx, w, http.StatusForbidden, "Access denied", "")
+		default:
+			logger.AppLogErrorCtx(ctx, "Failed to get URL stats",
+				zap.Error(err),
+				zap.String("short_code", shortCode),
+			)
+			respondWithError(ctx, w, http.StatusInternalServerError, "Internal server error", "")
+		}
+		return
+	}
+
+	series := make([]StatsSeriesPoint, 0, len(stats.Series))
+	for _, point := range stats.Series {
+		series = append(series, StatsSeriesPoint{
+			Start:  point.Start.Format(time.RFC3339),
+			Clicks: point.Count,
+		})
+	}
+
+	response := StatsResponse{
+		ShortCode:     stats.ShortCode,
+		TotalClicks:   stats.Total,
+		Bucket:        string(stats.Bucket),
+		From:          stats.From.Format(time.RFC3339),
+		To:            stats.To.Format(time.RFC3339),
+		Series:        series,
+		TopReferrers:  toStatsCounts(stats.TopReferrers),
+		TopUserAgents: toStatsCounts(stats.TopUserAgents),
+	}
+
+	respondWithJSON(ctx, w, http.StatusOK, response)
+}
+
+func toStatsCounts(counts []domain.ClickCount) []StatsCount {
+	items := make([]StatsCount, 0, len(counts))
+	for _, count := range counts {
+		items = append(items, StatsCount{Value: count.Value, Clicks: count.Count})
+	}
+
+	return items
+}
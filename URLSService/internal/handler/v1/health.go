@@ -7,21 +7,42 @@ import (
 	"time"
 
 	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/stream"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
+// LagReporter reports backlog of a background pipeline
+type LagReporter interface {
+	Lag(ctx context.Context) (*stream.GroupLag, error)
+}
+
+// DropReporter reports events a pipeline lost
+type DropReporter interface {
+	Dropped() int64
+}
+
 type HealthHandler struct {
 	pgPool       *pgxpool.Pool
 	redisClient  *redis.Client
+	clickLag     LagReporter
+	clickDrops   DropReporter
 	shuttingDown *atomic.Bool
 }
 
-func NewHealthHandler(pgPool *pgxpool.Pool, redisClient *redis.Client, shuttingDown *atomic.Bool) *HealthHandler {
+func NewHealthHandler(
+	pgPool *pgxpool.Pool,
+	redisClient *redis.Client,
+	clickLag LagReporter,
+	clickDrops DropReporter,
+	shuttingDown *atomic.Bool,
+) *HealthHandler {
 	return &HealthHandler{
 		pgPool:       pgPool,
 		redisClient:  redisClient,
+		clickLag:     clickLag,
+		clickDrops:   clickDrops,
 		shuttingDown: shuttingDown,
 	}
 }
@@ -76,5 +97,16 @@ func (h *HealthHandler) ReadinessCheck(w http.ResponseWriter, r *http.Request) {
 	}
 	response.Redis = "up"
 
+	// Click pipeline backlog is informational, analytics lagging behind doesn't stop redirects
+	if lag, err := h.clickLag.Lag(opCtx); err != nil {
+		logger.RedisLogWarnCtx(ctx, "Click stream lag check failed", zap.Error(err))
+	} else {
+		response.ClickStream = &StreamLagResponse{
+			Lag:     lag.Lag,
+			Pending: lag.Pending,
+			Dropped: h.clickDrops.Dropped(),
+		}
+	}
+
 	respondWithJSON(ctx, w, http.StatusOK, response)
 }
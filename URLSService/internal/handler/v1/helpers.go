@@ -3,12 +3,28 @@ package v1
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
 	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/service"
 	"go.uber.org/zap"
 )
 
+const (
+	// linkPasswordHeader carries password for protected links
+	linkPasswordHeader = "X-Link-Password"
+	// confirmParam confirms visit of interstitial links, same as on the warning page
+	confirmParam = "confirm"
+)
+
 // respondWithJSON is a helper function for consistent JSON responses
 func respondWithJSON(ctx context.Context, w http.ResponseWriter, statusCode int, payload interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -28,3 +44,195 @@ func respondWithError(ctx context.Context, w http.ResponseWriter, statusCode int
 	}
 	respondWithJSON(ctx, w, statusCode, errorResponse)
 }
+
+// visitorFromRequest collects client details, RemoteAddr is already resolved by RealIP middleware.
+// Country is taken from countryHeader of trusted proxy, empty header name skips it.
+func visitorFromRequest(r *http.Request, countryHeader string) *domain.Visitor {
+	visitor := &domain.Visitor{
+		UserAgent: r.UserAgent(),
+		Referrer:  r.Referer(),
+	}
+	if countryHeader != "" {
+		visitor.Country = r.Header.Get(countryHeader)
+	}
+
+	if addrPort, err := netip.ParseAddrPort(r.RemoteAddr); err == nil {
+		visitor.IP = addrPort.Addr()
+	} else if addr, err := netip.ParseAddr(r.RemoteAddr); err == nil {
+		visitor.IP = addr
+	}
+
+	if uid := r.Header.Get("X-User-Id"); uid != "" {
+		visitor.UserID = &uid
+	}
+
+	return visitor
+}
+
+// setRetryAfter sets Retry-After header when err carries lockout duration
+func setRetryAfter(w http.ResponseWriter, err error) {
+	var attemptsErr *service.TooManyAttemptsError
+	if errors.As(err, &attemptsErr) {
+		seconds := int(math.Ceil(attemptsErr.RetryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	}
+}
+
+// publicShortURL builds link visitors open, origin comes from baseURL or from request
+// as seen by the client behind proxy
+func publicShortURL(r *http.Request, baseURL string, shortCode string) string {
+	if baseURL == "" {
+		scheme := r.Header.Get("X-Forwarded-Proto")
+		if scheme == "" {
+			scheme = "http"
+			if r.TLS != nil {
+				scheme = "https"
+			}
+		}
+		host := r.Header.Get("X-Forwarded-Host")
+		if host == "" {
+			host = r.Host
+		}
+		baseURL = scheme + "://" + host
+	}
+
+	return baseURL + "/" + url.PathEscape(shortCode)
+}
+
+// etag formats URL version as strong entity tag
+func etag(version int) string {
+	return `"v` + strconv.Itoa(version) + `"`
+}
+
+// parseIfMatch returns versions listed in If-Match, nil when header is absent or "*".
+// Weak and unknown tags never match, so they produce an empty non-nil list.
+func parseIfMatch(header string) []int {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil
+	}
+
+	versions := make([]int, 0)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if !strings.HasPrefix(tag, `"v`) || !strings.HasSuffix(tag, `"`) {
+			continue
+		}
+		if version, err := strconv.Atoi(tag[2 : len(tag)-1]); err == nil {
+			versions = append(versions, version)
+		}
+	}
+
+	return versions
+}
+
+// newURLListItem converts URL to its owner facing representation
+func newURLListItem(url *domain.URL) URLListItem {
+	item := URLListItem{
+		ShortCode:   url.ShortCode,
+		OriginalURL: url.OriginalURL,
+		ExpiresAt:   url.ExpiresAt.Format(time.RFC3339),
+		CreatedAt:   url.CreatedAt.Format(time.RFC3339),
+		Title:       url.Title,
+
+		Active:            !url.Disabled,
+		PasswordProtected: url.PasswordHash != "",
+		MaxClicks:         url.MaxClicks,
+		ClicksUsed:        url.ClicksUsed,
+		Interstitial:      url.Interstitial,
+
+		Targets: newURLTargets(url.Targets),
+
+		Tags:       url.Tags,
+		Collection: url.Collection,
+	}
+	if url.DeletedAt != nil {
+		item.DeletedAt = url.DeletedAt.Format(time.RFC3339)
+	}
+	if meta := url.Metadata; meta != nil && meta.FetchError == "" {
+		item.Metadata = &URLMetadata{
+			Title:       meta.Title,
+			Description: meta.Description,
+			Image:       meta.ImageURL,
+			SiteName:    meta.SiteName,
+			Favicon:     meta.FaviconURL,
+			FetchedAt:   meta.FetchedAt.Format(time.RFC3339),
+		}
+	}
+	if url.Health != nil {
+		health := newURLHealth(url.Health)
+		item.Health = &health
+	}
+
+	return item
+}
+
+// newURLTargets converts targeting rules of link, nil when it has none
+func newURLTargets(rules []domain.TargetRule) []URLTarget {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	targets := make([]URLTarget, 0, len(rules))
+	for _, rule := range rules {
+		targets = append(targets, URLTarget{
+			OS:        string(rule.OS),
+			Device:    string(rule.Device),
+			Bot:       rule.Bot,
+			Countries: rule.Countries,
+			URL:       rule.URL,
+		})
+	}
+
+	return targets
+}
+
+// targetRules converts requested targeting rules, validation is left to the service
+func targetRules(targets []URLTarget) []domain.TargetRule {
+	if targets == nil {
+		return nil
+	}
+
+	rules := make([]domain.TargetRule, 0, len(targets))
+	for _, target := range targets {
+		rules = append(rules, domain.TargetRule{
+			OS:        domain.ClientOS(strings.ToLower(target.OS)),
+			Device:    domain.DeviceClass(strings.ToLower(target.Device)),
+			Bot:       target.Bot,
+			Countries: target.Countries,
+			URL:       target.URL,
+		})
+	}
+
+	return rules
+}
+
+// updatedTargetRules converts targeting rules of an edit, nil leaves them unchanged
+func updatedTargetRules(targets *[]URLTarget) *[]domain.TargetRule {
+	if targets == nil {
+		return nil
+	}
+
+	rules := targetRules(*targets)
+	return &rules
+}
+
+// newURLHealth converts destination check, nil health means link wasn't checked yet
+func newURLHealth(health *domain.LinkHealth) URLHealth {
+	if health == nil {
+		return URLHealth{Status: "unknown"}
+	}
+
+	response := URLHealth{
+		Status:              "healthy",
+		StatusCode:          health.StatusCode,
+		Error:               health.Error,
+		ConsecutiveFailures: health.ConsecutiveFailures,
+		CheckedAt:           health.CheckedAt.Format(time.RFC3339),
+	}
+	if !health.Healthy() {
+		response.Status = "failing"
+	}
+
+	return response
+}
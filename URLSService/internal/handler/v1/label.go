@@ -0,0 +1,207 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/service"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type LabelHandler struct {
+	service service.LabelService
+}
+
+func NewLabelHandler(service service.LabelService) *LabelHandler {
+	return &LabelHandler{service: service}
+}
+
+// SetURLTags replaces tags of an owned short URL
+func (h *LabelHandler) SetURLTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	shortCode := chi.URLParam(r, "shortCode")
+
+	// Get user ID from X-User-Id header (set by Traefik ForwardAuth)
+	userID := r.Header.Get("X-User-Id")
+	if userID == "" {
+		logger.AppLogInfoCtx(ctx, "No user ID provided for tags update")
+		respondWithError(ctx, w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	var req SetTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.AppLogErrorCtx(ctx, "Failed to decode request body", zap.Error(err))
+		respondWithError(ctx, w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	tags, err := h.service.SetURLTags(ctx, shortCode, userID, req.Tags)
+	if err != nil {
+		h.respondWithLabelError(w, r, err, "Failed to set URL tags")
+		return
+	}
+
+	respondWithJSON(ctx, w, http.StatusOK, SetTagsRequest{Tags: tags})
+}
+
+// SetURLCollection moves an owned short URL to collection, empty name removes it from any
+func (h *LabelHandler) SetURLCollection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	shortCode := chi.URLParam(r, "shortCode")
+
+	// Get user ID from X-User-Id header (set by Traefik ForwardAuth)
+	userID := r.Header.Get("X-User-Id")
+	if userID == "" {
+		logger.AppLogInfoCtx(ctx, "No user ID provided for collection update")
+		respondWithError(ctx, w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	var req SetCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.AppLogErrorCtx(ctx, "Failed to decode request body", zap.Error(err))
+		respondWithError(ctx, w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	collection, err := h.service.SetURLCollection(ctx, shortCode, userID, req.Collection)
+	if err != nil {
+		h.respondWithLabelError(w, r, err, "Failed to set URL collection")
+		return
+	}
+
+	respondWithJSON(ctx, w, http.StatusOK, SetCollectionRequest{Collection: collection})
+}
+
+// ListTags returns user's tags with link counts
+func (h *LabelHandler) ListTags(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, domain.LabelTag)
+}
+
+// ListCollections returns user's collections with link counts
+func (h *LabelHandler) ListCollections(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, domain.LabelCollection)
+}
+
+// RenameTag renames user's tag on every link carrying it
+func (h *LabelHandler) RenameTag(w http.ResponseWriter, r *http.Request) {
+	h.rename(w, r, domain.LabelTag)
+}
+
+// RenameCollection renames user's collection
+func (h *LabelHandler) RenameCollection(w http.ResponseWriter, r *http.Request) {
+	h.rename(w, r, domain.LabelCollection)
+}
+
+// DeleteTag removes user's tag from every link, links themselves are kept
+func (h *LabelHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	h.delete(w, r, domain.LabelTag)
+}
+
+// DeleteCollection removes user's collection, its links are kept outside of any
+func (h *LabelHandler) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	h.delete(w, r, domain.LabelCollection)
+}
+
+func (h *LabelHandler) list(w http.ResponseWriter, r *http.Request, kind domain.LabelKind) {
+	ctx := r.Context()
+
+	// Get user ID from X-User-Id header (set by Traefik ForwardAuth)
+	userID := r.Header.Get("X-User-Id")
+	if userID == "" {
+		logger.AppLogInfoCtx(ctx, "No user ID provided for labels list")
+		respondWithError(ctx, w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	labels, err := h.service.ListLabels(ctx, kind, userID)
+	if err != nil {
+		h.respondWithLabelError(w, r, err, "Failed to list labels")
+		return
+	}
+
+	response := LabelListResponse{
+		Labels: make([]LabelResponse, 0, len(labels)),
+	}
+	for _, label := range labels {
+		response.Labels = append(response.Labels, LabelResponse{
+			Name:     label.Name,
+			URLCount: label.URLCount,
+		})
+	}
+
+	respondWithJSON(ctx, w, http.StatusOK, response)
+}
+
+func (h *LabelHandler) rename(w http.ResponseWriter, r *http.Request, kind domain.LabelKind) {
+	ctx := r.Context()
+	name := chi.URLParam(r, "name")
+
+	// Get user ID from X-User-Id header (set by Traefik ForwardAuth)
+	userID := r.Header.Get("X-User-Id")
+	if userID == "" {
+		logger.AppLogInfoCtx(ctx, "No user ID provided for label rename")
+		respondWithError(ctx, w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	var req RenameLabelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.AppLogErrorCtx(ctx, "Failed to decode request body", zap.Error(err))
+		respondWithError(ctx, w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	newName, err := h.service.RenameLabel(ctx, kind, userID, name, req.Name)
+	if err != nil {
+		h.respondWithLabelError(w, r, err, "Failed to rename label")
+		return
+	}
+
+	respondWithJSON(ctx, w, http.StatusOK, RenameLabelRequest{Name: newName})
+}
+
+func (h *LabelHandler) delete(w http.ResponseWriter, r *http.Request, kind domain.LabelKind) {
+	ctx := r.Context()
+	name := chi.URLParam(r, "name")
+
+	// Get user ID from X-User-Id header (set by Traefik ForwardAuth)
+	userID := r.Header.Get("X-User-Id")
+	if userID == "" {
+		logger.AppLogInfoCtx(ctx, "No user ID provided for label delete")
+		respondWithError(ctx, w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	if err := h.service.DeleteLabel(ctx, kind, userID, name); err != nil {
+		h.respondWithLabelError(w, r, err, "Failed to delete label")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *LabelHandler) respondWithLabelError(w http.ResponseWriter, r *http.Request, err error, logMessage string) {
+	ctx := r.Context()
+
+	switch {
+	case errors.Is(err, service.ErrInvalidLabel):
+		respondWithError(ctx, w, http.StatusBadRequest, "Invalid label", err.Error())
+	case errors.Is(err, service.ErrInvalidShortCode):
+		respondWithError(ctx, w, http.StatusBadRequest, "Short code is required", "")
+	case errors.Is(err, service.ErrNotFound):
+		respondWithError(ctx, w, http.StatusNotFound, "Not found", "")
+	case errors.Is(err, service.ErrLabelExists):
+		respondWithError(ctx, w, http.StatusConflict, "Label already exists", err.Error())
+	case errors.Is(err, service.ErrForbidden):
+		respondWithError(ctx, w, http.StatusForbidden, "Access denied", "")
+	default:
+		logger.AppLogErrorCtx(ctx, logMessage, zap.Error(err))
+		respondWithError(ctx, w, http.StatusInternalServerError, "Internal server error", "")
+	}
+}
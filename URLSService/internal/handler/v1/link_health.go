@@ -0,0 +1,61 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/service"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type LinkHealthHandler struct {
+	service service.LinkHealthService
+}
+
+func NewLinkHealthHandler(service service.LinkHealthService) *LinkHealthHandler {
+	return &LinkHealthHandler{service: service}
+}
+
+// Health returns latest destination check of a short URL owned by the current user
+func (h *LinkHealthHandler) Health(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	shortCode := chi.URLParam(r, "shortCode")
+
+	// Get user ID from X-User-Id header (set by Traefik ForwardAuth)
+	userID := r.Header.Get("X-User-Id")
+	if userID == "" {
+		logger.AppLogInfoCtx(ctx, "No user ID provided for link health operation")
+		respondWithError(ctx, w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	health, err := h.service.GetHealth(ctx, shortCode, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidShortCode):
+			respondWithError(ctx, w, http.StatusBadRequest, "Short code is required", "")
+		case errors.Is(err, service.ErrNotFound):
+			logger.AppLogInfoCtx(ctx, "URL not found",
+				zap.String("short_code", shortCode),
+			)
+			respondWithError(ctx, w, http.StatusNotFound, "URL not found", "")
+		case errors.Is(err, service.ErrForbidden):
+			logger.AppLogInfoCtx(ctx, "Access denied for link health",
+				zap.String("short_code", shortCode),
+				zap.String("user_id", userID),
+			)
+			respondWithError(ctx, w, http.StatusForbidden, "Access denied", "")
+		default:
+			logger.AppLogErrorCtx(ctx, "Failed to get link health",
+				zap.Error(err),
+				zap.String("short_code", shortCode),
+			)
+			respondWithError(ctx, w, http.StatusInternalServerError, "Internal server error", "")
+		}
+		return
+	}
+
+	respondWithJSON(ctx, w, http.StatusOK, newURLHealth(health))
+}
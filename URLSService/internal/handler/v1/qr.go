@@ -0,0 +1,186 @@
+package v1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image/color"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/config"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/service"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultQRMargin = 4
+	defaultQRLevel  = "M"
+)
+
+var qrContentTypes = map[string]string{
+	service.QRFormatPNG: "image/png",
+	service.QRFormatSVG: "image/svg+xml",
+}
+
+type QRHandler struct {
+	service  service.URLService
+	config   *config.QRConfig
+	redirect *config.RedirectConfig
+}
+
+func NewQRHandler(service service.URLService, config *config.QRConfig, redirect *config.RedirectConfig) *QRHandler {
+	return &QRHandler{
+		service:  service,
+		config:   config,
+		redirect: redirect,
+	}
+}
+
+// QR renders QR code of public short link as PNG or SVG
+func (h *QRHandler) QR(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	shortCode := chi.URLParam(r, "shortCode")
+
+	opts, err := h.parseOptions(r)
+	if err != nil {
+		respondWithError(ctx, w, http.StatusBadRequest, "Invalid QR code options", err.Error())
+		return
+	}
+
+	// Protected links get a code too, it reveals nothing but the short link itself
+	url, err := h.service.LookupURL(ctx, shortCode)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotFound), errors.Is(err, service.ErrExpired),
+			errors.Is(err, service.ErrInvalidShortCode):
+			respondWithError(ctx, w, http.StatusNotFound, "URL not found", "")
+		case errors.Is(err, service.ErrURLBlocked):
+			respondWithError(ctx, w, http.StatusForbidden, "URL disabled", err.Error())
+		case errors.Is(err, service.ErrDisabled):
+			respondWithError(ctx, w, http.StatusGone, "URL disabled", err.Error())
+		default:
+			logger.AppLogErrorCtx(ctx, "Failed to get URL",
+				zap.Error(err),
+				zap.String("short_code", shortCode),
+			)
+			respondWithError(ctx, w, http.StatusInternalServerError, "Internal server error", "")
+		}
+		return
+	}
+
+	content := publicShortURL(r, h.redirect.PublicBaseURL(), url.ShortCode)
+	tag := qrETag(content, opts)
+
+	w.Header().Set("Vary", "Accept")
+	w.Header().Set("ETag", tag)
+	h.setCacheHeaders(w, url)
+	if r.Header.Get("If-None-Match") == tag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	image, err := service.RenderQRCode(content, opts)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidQROptions) {
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid QR code options", err.Error())
+			return
+		}
+		logger.AppLogErrorCtx(ctx, "Failed to render QR code",
+			zap.Error(err),
+			zap.String("short_code", shortCode),
+		)
+		respondWithError(ctx, w, http.StatusInternalServerError, "Internal server error", "")
+		return
+	}
+
+	w.Header().Set("Content-Type", qrContentTypes[opts.Format])
+	w.Header().Set("Content-Length", strconv.Itoa(len(image)))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(image); err != nil {
+		logger.AppLogErrorCtx(ctx, "Failed to write QR code", zap.Error(err))
+	}
+}
+
+// parseOptions reads format, size, margin, level, fg and bg query params,
+// format falls back to Accept header and then to PNG
+func (h *QRHandler) parseOptions(r *http.Request) (service.QROptions, error) {
+	query := r.URL.Query()
+	opts := service.QROptions{
+		Format:     query.Get("format"),
+		Size:       h.config.DefaultSize(),
+		Margin:     defaultQRMargin,
+		Level:      strings.ToUpper(query.Get("level")),
+		Foreground: color.NRGBA{A: 0xff},
+		Background: color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	}
+
+	if opts.Format == "" {
+		opts.Format = service.QRFormatPNG
+		accept := r.Header.Get("Accept")
+		if strings.Contains(accept, qrContentTypes[service.QRFormatSVG]) && !strings.Contains(accept, qrContentTypes[service.QRFormatPNG]) {
+			opts.Format = service.QRFormatSVG
+		}
+	}
+	if _, ok := qrContentTypes[opts.Format]; !ok {
+		return opts, fmt.Errorf("format must be png or svg")
+	}
+	if opts.Level == "" {
+		opts.Level = defaultQRLevel
+	}
+
+	if s := query.Get("size"); s != "" {
+		size, err := strconv.Atoi(s)
+		if err != nil || size <= 0 || size > h.config.MaxSize() {
+			return opts, fmt.Errorf("size must be between 1 and %d", h.config.MaxSize())
+		}
+		opts.Size = size
+	}
+
+	if s := query.Get("margin"); s != "" {
+		margin, err := strconv.Atoi(s)
+		if err != nil {
+			return opts, fmt.Errorf("margin must be an integer")
+		}
+		opts.Margin = margin
+	}
+
+	var err error
+	if s := query.Get("fg"); s != "" {
+		if opts.Foreground, err = service.ParseQRColor(s); err != nil {
+			return opts, err
+		}
+	}
+	if s := query.Get("bg"); s != "" {
+		if opts.Background, err = service.ParseQRColor(s); err != nil {
+			return opts, err
+		}
+	}
+
+	return opts, nil
+}
+
+// setCacheHeaders lets clients keep the image no longer than link lives
+func (h *QRHandler) setCacheHeaders(w http.ResponseWriter, url *domain.URL) {
+	maxAge := min(time.Until(url.ExpiresAt), h.config.CacheMaxAge())
+	seconds := int(maxAge / time.Second)
+	if seconds <= 0 {
+		w.Header().Set("Cache-Control", "no-store")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(seconds))
+	w.Header().Set("Expires", time.Now().Add(maxAge).UTC().Format(http.TimeFormat))
+}
+
+// qrETag identifies image by everything it is rendered from
+func qrETag(content string, opts service.QROptions) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%+v", content, opts)))
+	return `"qr-` + hex.EncodeToString(sum[:8]) + `"`
+}
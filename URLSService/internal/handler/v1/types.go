@@ -2,14 +2,67 @@ package v1
 
 // CreateURLRequest represents the request to create a short URL
 type CreateURLRequest struct {
-	URL string `json:"url" example:"https://example.com"`
-	TTL int    `json:"ttl" example:"3600"`
+	URL          string   `json:"url" example:"https://example.com"`
+	TTL          int      `json:"ttl" example:"3600"`
+	RedirectType int      `json:"redirect_type,omitempty" example:"301"`
+	Alias        string   `json:"alias,omitempty" example:"spring-sale"`
+	Password     string   `json:"password,omitempty" example:"s3cret"`
+	MaxClicks    int      `json:"max_clicks,omitempty" example:"1"`
+	Tags         []string `json:"tags,omitempty" example:"work,reading list"`
+	Collection   string   `json:"collection,omitempty" example:"Spring campaign"`
+	Dedupe       bool     `json:"dedupe,omitempty" example:"true"`
+	Title        string   `json:"title,omitempty" example:"Spring sale landing page"`
+	Interstitial bool     `json:"interstitial,omitempty" example:"false"`
+	// Device or country specific destinations tried in order, url is used when none matches
+	Targets []URLTarget `json:"targets,omitempty"`
 }
 
-// URLResponse represents the response after creating a short URL
+// URLTarget represents a targeting rule, visitor must match every condition set
+type URLTarget struct {
+	OS     string `json:"os,omitempty" example:"ios" enums:"ios,android,windows,macos,linux,chromeos"`
+	Device string `json:"device,omitempty" example:"mobile" enums:"mobile,tablet,desktop"`
+	Bot    *bool  `json:"bot,omitempty" example:"false"`
+	// ISO 3166-1 alpha-2 codes, visitor must be in one of them
+	Countries []string `json:"countries,omitempty" example:"DE,AT"`
+	URL       string   `json:"url" example:"https://apps.apple.com/app/id123456789"`
+}
+
+// BatchCreateURLRequest represents the request to create many short URLs at once
+type BatchCreateURLRequest struct {
+	Items []CreateURLRequest `json:"items"`
+}
+
+// BatchCreateURLResult represents outcome of one batch item, either short URL or error is set
+type BatchCreateURLResult struct {
+	ShortURL  string `json:"short_url,omitempty" example:"abc123"`
+	ExpiresAt string `json:"expires_at,omitempty" example:"2025-11-10T12:00:00Z"`
+	Existing  bool   `json:"existing,omitempty" example:"false"`
+	Error     string `json:"error,omitempty" example:"Alias already taken"`
+	Message   string `json:"message,omitempty" example:"alias already taken"`
+}
+
+// BatchCreateURLResponse represents per-item results in request order
+type BatchCreateURLResponse struct {
+	Results []BatchCreateURLResult `json:"results"`
+}
+
+// UpdateURLRequest represents the request to edit a short URL, omitted fields are left unchanged
+type UpdateURLRequest struct {
+	URL          *string `json:"url,omitempty" example:"https://example.com/fixed"`
+	TTL          *int    `json:"ttl,omitempty" example:"1440"`
+	Active       *bool   `json:"active,omitempty" example:"false"`
+	Title        *string `json:"title,omitempty" example:"Spring sale landing page"`
+	Interstitial *bool   `json:"interstitial,omitempty" example:"true"`
+	// Replaces all targeting rules, empty list removes them
+	Targets *[]URLTarget `json:"targets,omitempty"`
+}
+
+// URLResponse represents the response after creating a short URL,
+// existing is set when dedupe returned user's link instead of creating one
 type URLResponse struct {
 	ShortURL  string `json:"short_url" example:"abc123"`
 	ExpiresAt string `json:"expires_at" example:"2025-11-10T12:00:00Z"`
+	Existing  bool   `json:"existing,omitempty" example:"false"`
 }
 
 // URLDataResponse represents the response when retrieving URL data
@@ -24,11 +77,100 @@ type URLListItem struct {
 	OriginalURL string `json:"original_url" example:"https://example.com"`
 	ExpiresAt   string `json:"expires_at" example:"2025-11-10T12:00:00Z"`
 	CreatedAt   string `json:"created_at" example:"2025-11-10T10:00:00Z"`
+	DeletedAt   string `json:"deleted_at,omitempty" example:"2025-11-11T09:00:00Z"`
+	Title       string `json:"title,omitempty" example:"Spring sale landing page"`
+
+	Active            bool `json:"active" example:"true"`
+	PasswordProtected bool `json:"password_protected" example:"false"`
+	MaxClicks         int  `json:"max_clicks,omitempty" example:"10"`
+	ClicksUsed        int  `json:"clicks_used,omitempty" example:"3"`
+	Interstitial      bool `json:"interstitial" example:"false"`
+
+	Targets []URLTarget `json:"targets,omitempty"`
+
+	Tags       []string `json:"tags,omitempty" example:"work,reading list"`
+	Collection string   `json:"collection,omitempty" example:"Spring campaign"`
+
+	// Details of destination page, absent until fetched or when page couldn't be read
+	Metadata *URLMetadata `json:"metadata,omitempty"`
+	// Latest check of destination, absent until checked
+	Health *URLHealth `json:"health,omitempty"`
+}
+
+// URLHealth represents latest destination check of a short URL
+type URLHealth struct {
+	Status              string `json:"status" example:"healthy" enums:"healthy,failing,unknown"`
+	StatusCode          int    `json:"status_code,omitempty" example:"200"`
+	Error               string `json:"error,omitempty" example:"dial tcp: lookup example.com: no such host"`
+	ConsecutiveFailures int    `json:"consecutive_failures" example:"0"`
+	CheckedAt           string `json:"checked_at,omitempty" example:"2025-11-10T10:00:00Z"`
+}
+
+// URLMetadata represents destination page details fetched after link creation
+type URLMetadata struct {
+	Title       string `json:"title,omitempty" example:"Spring Sale - Example Store"`
+	Description string `json:"description,omitempty" example:"Up to 50% off selected items"`
+	Image       string `json:"image,omitempty" example:"https://example.com/og/sale.png"`
+	SiteName    string `json:"site_name,omitempty" example:"Example Store"`
+	Favicon     string `json:"favicon,omitempty" example:"https://example.com/favicon.ico"`
+	FetchedAt   string `json:"fetched_at" example:"2025-11-10T10:00:05Z"`
 }
 
 // URLListResponse represents the response when listing user URLs
 type URLListResponse struct {
-	URLs []URLListItem `json:"urls"`
+	URLs       []URLListItem `json:"urls"`
+	NextCursor string        `json:"next_cursor,omitempty" example:"MTczMTIzNDU2Nzg5MDEyOmFiYzEyMw"`
+	Total      *int          `json:"total,omitempty" example:"42"`
+}
+
+// SetTagsRequest represents the full set of tags for a short URL, empty list removes all
+type SetTagsRequest struct {
+	Tags []string `json:"tags" example:"work,reading list"`
+}
+
+// SetCollectionRequest represents collection of a short URL, empty name removes it from any
+type SetCollectionRequest struct {
+	Collection string `json:"collection" example:"Spring campaign"`
+}
+
+// RenameLabelRequest represents new name of a tag or collection
+type RenameLabelRequest struct {
+	Name string `json:"name" example:"work"`
+}
+
+// LabelResponse represents user's tag or collection
+type LabelResponse struct {
+	Name     string `json:"name" example:"work"`
+	URLCount int    `json:"url_count" example:"12"`
+}
+
+// LabelListResponse represents user's tags or collections ordered by name
+type LabelListResponse struct {
+	Labels []LabelResponse `json:"labels"`
+}
+
+// StatsSeriesPoint represents clicks within one time bucket
+type StatsSeriesPoint struct {
+	Start  string `json:"start" example:"2025-11-10T00:00:00Z"`
+	Clicks int64  `json:"clicks" example:"42"`
+}
+
+// StatsCount represents clicks for a single referrer or user agent
+type StatsCount struct {
+	Value  string `json:"value" example:"https://t.co/"`
+	Clicks int64  `json:"clicks" example:"17"`
+}
+
+// StatsResponse represents click statistics of a short URL
+type StatsResponse struct {
+	ShortCode     string             `json:"short_code" example:"abc123"`
+	TotalClicks   int64              `json:"total_clicks" example:"1024"`
+	Bucket        string             `json:"bucket" example:"day"`
+	From          string             `json:"from" example:"2025-10-11T00:00:00Z"`
+	To            string             `json:"to" example:"2025-11-10T12:00:00Z"`
+	Series        []StatsSeriesPoint `json:"series"`
+	TopReferrers  []StatsCount       `json:"top_referrers"`
+	TopUserAgents []StatsCount       `json:"top_user_agents"`
 }
 
 // ErrorResponse represents an error response
@@ -42,10 +184,18 @@ type HealthResponse struct {
 	Status string `json:"status" example:"ok"`
 }
 
+// StreamLagResponse represents consumer group backlog
+type StreamLagResponse struct {
+	Lag     int64 `json:"lag" example:"12"`
+	Pending int64 `json:"pending" example:"3"`
+	Dropped int64 `json:"dropped" example:"0"` // clicks this replica failed to publish since start
+}
+
 // ReadinessResponse represents readiness check response
 type ReadinessResponse struct {
-	Status   string `json:"status" example:"ok"`
-	Postgres string `json:"postgres,omitempty" example:"up"`
-	Redis    string `json:"redis,omitempty" example:"up"`
-	Reason   string `json:"reason,omitempty" example:"shutting down"`
+	Status      string             `json:"status" example:"ok"`
+	Postgres    string             `json:"postgres,omitempty" example:"up"`
+	Redis       string             `json:"redis,omitempty" example:"up"`
+	ClickStream *StreamLagResponse `json:"click_stream,omitempty"`
+	Reason      string             `json:"reason,omitempty" example:"shutting down"`
 }
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/config"
 	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
 	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/service"
 	"github.com/go-chi/chi/v5"
@@ -15,10 +16,14 @@ import (
 
 type URLHandler struct {
 	service service.URLService
+	geoip   *config.GeoIPConfig
 }
 
-func NewURLHandler(service service.URLService) *URLHandler {
-	return &URLHandler{service: service}
+func NewURLHandler(service service.URLService, geoip *config.GeoIPConfig) *URLHandler {
+	return &URLHandler{
+		service: service,
+		geoip:   geoip,
+	}
 }
 
 // Create creates a new short URL
@@ -38,7 +43,21 @@ func (h *URLHandler) Create(w http.ResponseWriter, r *http.Request) {
 		userID = &uid
 	}
 
-	url, err := h.service.CreateShortURL(ctx, req.URL, req.TTL, userID)
+	url, existing, err := h.service.CreateShortURL(ctx, service.CreateURLParams{
+		OriginalURL:  req.URL,
+		TTLMinutes:   req.TTL,
+		RedirectType: req.RedirectType,
+		Alias:        req.Alias,
+		Password:     req.Password,
+		MaxClicks:    req.MaxClicks,
+		Tags:         req.Tags,
+		Collection:   req.Collection,
+		Dedupe:       req.Dedupe,
+		Title:        req.Title,
+		Interstitial: req.Interstitial,
+		Targets:      targetRules(req.Targets),
+		UserID:       userID,
+	})
 	if err != nil {
 		switch {
 		case errors.Is(err, service.ErrInvalidURL):
@@ -53,6 +72,39 @@ func (h *URLHandler) Create(w http.ResponseWriter, r *http.Request) {
 				zap.Error(err),
 			)
 			respondWithError(ctx, w, http.StatusBadRequest, "Invalid TTL", err.Error())
+		case errors.Is(err, service.ErrInvalidRedirectType):
+			logger.AppLogInfoCtx(ctx, "Invalid redirect type provided",
+				zap.Int("redirect_type", req.RedirectType),
+				zap.Error(err),
+			)
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid redirect type", err.Error())
+		case errors.Is(err, service.ErrInvalidAlias):
+			logger.AppLogInfoCtx(ctx, "Invalid alias provided",
+				zap.String("alias", req.Alias),
+				zap.Error(err),
+			)
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid alias", err.Error())
+		case errors.Is(err, service.ErrAliasTaken):
+			logger.AppLogInfoCtx(ctx, "Alias already taken",
+				zap.String("alias", req.Alias),
+			)
+			respondWithError(ctx, w, http.StatusConflict, "Alias already taken", err.Error())
+		case errors.Is(err, service.ErrInvalidMaxClicks):
+			logger.AppLogInfoCtx(ctx, "Invalid max clicks provided",
+				zap.Int("max_clicks", req.MaxClicks),
+			)
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid max clicks", err.Error())
+		case errors.Is(err, service.ErrInvalidPassword):
+			logger.AppLogInfoCtx(ctx, "Invalid password provided", zap.Error(err))
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid password", err.Error())
+		case errors.Is(err, service.ErrInvalidLabel):
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid label", err.Error())
+		case errors.Is(err, service.ErrInvalidDedupe):
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid dedupe", err.Error())
+		case errors.Is(err, service.ErrInvalidTitle):
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid title", err.Error())
+		case errors.Is(err, service.ErrInvalidTargeting):
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid targeting", err.Error())
 		default:
 			logger.AppLogErrorCtx(ctx, "Failed to create URL",
 				zap.Error(err),
@@ -66,12 +118,19 @@ func (h *URLHandler) Create(w http.ResponseWriter, r *http.Request) {
 	response := URLResponse{
 		ShortURL:  url.ShortCode,
 		ExpiresAt: url.ExpiresAt.Format(time.RFC3339),
+		Existing:  existing,
 	}
 
-	respondWithJSON(ctx, w, http.StatusCreated, response)
+	// Nothing is created when dedupe found user's link to the same destination
+	status := http.StatusCreated
+	if existing {
+		status = http.StatusOK
+	}
+	respondWithJSON(ctx, w, status, response)
 }
 
-// Get retrieves the original URL by short code
+// Get resolves short code the same way redirect does: it spends a click of click-limited links,
+// records the visit, applies targeting and needs ?confirm=1 for interstitial links
 func (h *URLHandler) Get(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	shortCode := chi.URLParam(r, "shortCode")
@@ -82,14 +141,33 @@ func (h *URLHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	url, err := h.service.GetURL(ctx, shortCode)
+	// Response reveals destination of this visit, it must not be reused for another one
+	w.Header().Set("Cache-Control", "no-store")
+
+	url, err := h.service.ResolveURL(ctx, shortCode, r.Header.Get(linkPasswordHeader),
+		visitorFromRequest(r, h.geoip.CountryHeader()), r.URL.Query().Has(confirmParam))
 	if err != nil {
 		switch {
-		case errors.Is(err, service.ErrNotFound):
+		case errors.Is(err, service.ErrNotFound), errors.Is(err, service.ErrExpired):
 			logger.AppLogInfoCtx(ctx, "URL not found",
 				zap.String("short_code", shortCode),
 			)
 			respondWithError(ctx, w, http.StatusNotFound, "URL not found", "")
+		case errors.Is(err, service.ErrURLBlocked):
+			respondWithError(ctx, w, http.StatusForbidden, "URL disabled", err.Error())
+		case errors.Is(err, service.ErrDisabled):
+			respondWithError(ctx, w, http.StatusGone, "URL disabled", err.Error())
+		case errors.Is(err, service.ErrPasswordRequired):
+			respondWithError(ctx, w, http.StatusUnauthorized, "password_required",
+				"Link is password protected, send password in "+linkPasswordHeader+" header")
+		case errors.Is(err, service.ErrWrongPassword):
+			respondWithError(ctx, w, http.StatusUnauthorized, "invalid_password", err.Error())
+		case errors.Is(err, service.ErrTooManyAttempts):
+			setRetryAfter(w, err)
+			respondWithError(ctx, w, http.StatusTooManyRequests, "too_many_attempts", err.Error())
+		case errors.Is(err, service.ErrConfirmationRequired):
+			respondWithError(ctx, w, http.StatusConflict, "confirmation_required",
+				"Link shows a warning before redirect, repeat request with ?"+confirmParam+"=1 to continue")
 		default:
 			logger.AppLogErrorCtx(ctx, "Failed to get URL",
 				zap.Error(err),
@@ -105,11 +183,21 @@ func (h *URLHandler) Get(w http.ResponseWriter, r *http.Request) {
 		ExpiresAt:   url.ExpiresAt.Format(time.RFC3339),
 	}
 
+	w.Header().Set("ETag", etag(url.Version))
 	respondWithJSON(ctx, w, http.StatusOK, response)
 }
 
 // List returns all URLs for the current user
 func (h *URLHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, false)
+}
+
+// ListTrash returns deleted URLs of the current user that can still be restored
+func (h *URLHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, true)
+}
+
+func (h *URLHandler) list(w http.ResponseWriter, r *http.Request, trash bool) {
 	ctx := r.Context()
 
 	// Get user ID from X-User-Id header (set by Traefik ForwardAuth)
@@ -121,22 +209,61 @@ func (h *URLHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse query params
-	limit := 20
-	offset := 0
-	if l := r.URL.Query().Get("limit"); l != "" {
+	query := r.URL.Query()
+	params := service.ListURLsParams{
+		UserID: userID,
+		Limit:  20,
+		Cursor: query.Get("cursor"),
+		Trash:  trash,
+	}
+	if l := query.Get("limit"); l != "" {
 		if parsed, err := strconv.Atoi(l); err == nil {
-			limit = parsed
+			params.Limit = parsed
 		}
 	}
-	if o := r.URL.Query().Get("offset"); o != "" {
+	if o := query.Get("offset"); o != "" && params.Cursor == "" {
 		if parsed, err := strconv.Atoi(o); err == nil {
-			offset = parsed
+			params.Offset = parsed
+		}
+		// Offset is kept for the current frontend only, next_cursor replaces it
+		w.Header().Set("Deprecation", "true")
+	}
+	if t := query.Get("include_total"); t != "" {
+		params.WithTotal, _ = strconv.ParseBool(t)
+	}
+
+	params.Host = query.Get("host")
+	params.Search = query.Get("q")
+	params.Status = query.Get("status")
+	params.Sort = query.Get("sort")
+	params.Order = query.Get("order")
+	params.Tags = query["tag"]
+	params.Collection = query.Get("collection")
+	for name, target := range map[string]*time.Time{
+		"created_from": &params.CreatedAfter,
+		"created_to":   &params.CreatedBefore,
+		"expires_from": &params.ExpiresAfter,
+		"expires_to":   &params.ExpiresBefore,
+	} {
+		v := query.Get(name)
+		if v == "" {
+			continue
 		}
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid "+name, "expected RFC3339 timestamp")
+			return
+		}
+		*target = parsed
 	}
 
-	urls, err := h.service.GetUserURLs(ctx, userID, limit, offset)
+	page, err := h.service.GetUserURLs(ctx, params)
 	if err != nil {
 		switch {
+		case errors.Is(err, service.ErrInvalidCursor):
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid cursor", err.Error())
+		case errors.Is(err, service.ErrInvalidFilter):
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid filter", err.Error())
 		case errors.Is(err, service.ErrForbidden):
 			respondWithError(ctx, w, http.StatusForbidden, "Access denied", "")
 		default:
@@ -149,17 +276,16 @@ func (h *URLHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	items := make([]URLListItem, 0, len(urls))
-	for _, url := range urls {
-		items = append(items, URLListItem{
-			ShortCode:   url.ShortCode,
-			OriginalURL: url.OriginalURL,
-			ExpiresAt:   url.ExpiresAt.Format(time.RFC3339),
-			CreatedAt:   url.CreatedAt.Format(time.RFC3339),
-		})
+	items := make([]URLListItem, 0, len(page.URLs))
+	for _, url := range page.URLs {
+		items = append(items, newURLListItem(url))
 	}
 
-	response := URLListResponse{URLs: items}
+	response := URLListResponse{
+		URLs:       items,
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	}
 	respondWithJSON(ctx, w, http.StatusOK, response)
 }
 
@@ -208,3 +334,122 @@ func (h *URLHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// Restore takes a short URL out of trash, it resolves again right away
+func (h *URLHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	shortCode := chi.URLParam(r, "shortCode")
+
+	// Get user ID from X-User-Id header (set by Traefik ForwardAuth)
+	userID := r.Header.Get("X-User-Id")
+	if userID == "" {
+		logger.AppLogInfoCtx(ctx, "No user ID provided for restore operation")
+		respondWithError(ctx, w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	url, err := h.service.RestoreURL(ctx, shortCode, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidShortCode):
+			respondWithError(ctx, w, http.StatusBadRequest, "Short code is required", "")
+		case errors.Is(err, service.ErrNotFound):
+			logger.AppLogInfoCtx(ctx, "URL not found in trash",
+				zap.String("short_code", shortCode),
+			)
+			respondWithError(ctx, w, http.StatusNotFound, "URL not found in trash", "")
+		default:
+			logger.AppLogErrorCtx(ctx, "Failed to restore URL",
+				zap.Error(err),
+				zap.String("short_code", shortCode),
+			)
+			respondWithError(ctx, w, http.StatusInternalServerError, "Internal server error", "")
+		}
+		return
+	}
+
+	w.Header().Set("ETag", etag(url.Version))
+	respondWithJSON(ctx, w, http.StatusOK, newURLListItem(url))
+}
+
+// Update edits destination, expiry or active flag of an owned short URL
+func (h *URLHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	shortCode := chi.URLParam(r, "shortCode")
+
+	if shortCode == "" {
+		logger.AppLogInfoCtx(ctx, "Empty short code provided")
+		respondWithError(ctx, w, http.StatusBadRequest, "Short code is required", "")
+		return
+	}
+
+	// Get user ID from X-User-Id header (set by Traefik ForwardAuth)
+	userID := r.Header.Get("X-User-Id")
+	if userID == "" {
+		logger.AppLogInfoCtx(ctx, "No user ID provided for update operation")
+		respondWithError(ctx, w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	var req UpdateURLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.AppLogErrorCtx(ctx, "Failed to decode request body", zap.Error(err))
+		respondWithError(ctx, w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	url, err := h.service.UpdateURL(ctx, shortCode, userID, service.UpdateURLParams{
+		OriginalURL:  req.URL,
+		TTLMinutes:   req.TTL,
+		Active:       req.Active,
+		Title:        req.Title,
+		Interstitial: req.Interstitial,
+		Targets:      updatedTargetRules(req.Targets),
+		IfMatch:      parseIfMatch(r.Header.Get("If-Match")),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNothingToUpdate):
+			respondWithError(ctx, w, http.StatusBadRequest, "Nothing to update", err.Error())
+		case errors.Is(err, service.ErrInvalidURL):
+			logger.AppLogInfoCtx(ctx, "Invalid URL provided",
+				zap.Error(err),
+				zap.String("short_code", shortCode),
+			)
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid URL", err.Error())
+		case errors.Is(err, service.ErrInvalidTTL):
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid TTL", err.Error())
+		case errors.Is(err, service.ErrInvalidTitle):
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid title", err.Error())
+		case errors.Is(err, service.ErrInvalidTargeting):
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid targeting", err.Error())
+		case errors.Is(err, service.ErrNotFound):
+			logger.AppLogInfoCtx(ctx, "URL not found",
+				zap.String("short_code", shortCode),
+			)
+			respondWithError(ctx, w, http.StatusNotFound, "URL not found", "")
+		case errors.Is(err, service.ErrForbidden):
+			logger.AppLogInfoCtx(ctx, "Access denied for URL update",
+				zap.String("short_code", shortCode),
+				zap.String("user_id", userID),
+			)
+			respondWithError(ctx, w, http.StatusForbidden, "Access denied", "")
+		case errors.Is(err, service.ErrVersionMismatch):
+			var mismatchErr *service.VersionMismatchError
+			if errors.As(err, &mismatchErr) {
+				w.Header().Set("ETag", etag(mismatchErr.Current))
+			}
+			respondWithError(ctx, w, http.StatusPreconditionFailed, "URL was modified", err.Error())
+		default:
+			logger.AppLogErrorCtx(ctx, "Failed to update URL",
+				zap.Error(err),
+				zap.String("short_code", shortCode),
+			)
+			respondWithError(ctx, w, http.StatusInternalServerError, "Internal server error", "")
+		}
+		return
+	}
+
+	w.Header().Set("ETag", etag(url.Version))
+	respondWithJSON(ctx, w, http.StatusOK, newURLListItem(url))
+}
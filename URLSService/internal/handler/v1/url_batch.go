@@ -0,0 +1,124 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/service"
+	"go.uber.org/zap"
+)
+
+// maxBatchBodySize bounds request body, generous for MaxBatchSize items of max length URLs
+const maxBatchBodySize = 8 << 20
+
+// CreateBatch creates many short URLs in one request
+func (h *URLHandler) CreateBatch(w http.ResponseWriter, r *http.Request) {
+	var req BatchCreateURLRequest
+	ctx := r.Context()
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBatchBodySize)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.AppLogErrorCtx(ctx, "Failed to decode request body", zap.Error(err))
+		respondWithError(ctx, w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	// Get user ID from X-User-Id header (set by Traefik ForwardAuth)
+	var userID *string
+	if uid := r.Header.Get("X-User-Id"); uid != "" {
+		userID = &uid
+	}
+
+	items := make([]service.CreateURLParams, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, service.CreateURLParams{
+			OriginalURL:  item.URL,
+			TTLMinutes:   item.TTL,
+			RedirectType: item.RedirectType,
+			Alias:        item.Alias,
+			Password:     item.Password,
+			MaxClicks:    item.MaxClicks,
+			Tags:         item.Tags,
+			Collection:   item.Collection,
+			Dedupe:       item.Dedupe,
+			Title:        item.Title,
+			Interstitial: item.Interstitial,
+			Targets:      targetRules(item.Targets),
+			UserID:       userID,
+		})
+	}
+
+	results, err := h.service.CreateShortURLs(ctx, items)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidBatch):
+			respondWithError(ctx, w, http.StatusBadRequest, "Invalid batch", err.Error())
+		default:
+			logger.AppLogErrorCtx(ctx, "Failed to create URL batch",
+				zap.Error(err),
+				zap.Int("items", len(items)),
+			)
+			respondWithError(ctx, w, http.StatusInternalServerError, "Internal server error", "")
+		}
+		return
+	}
+
+	response := BatchCreateURLResponse{
+		Results: make([]BatchCreateURLResult, 0, len(results)),
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			title, message := createErrorDetails(result.Err)
+			if message == "" {
+				logger.AppLogErrorCtx(ctx, "Failed to create batch item", zap.Error(result.Err))
+			}
+			response.Results = append(response.Results, BatchCreateURLResult{
+				Error:   title,
+				Message: message,
+			})
+			continue
+		}
+
+		response.Results = append(response.Results, BatchCreateURLResult{
+			ShortURL:  result.URL.ShortCode,
+			ExpiresAt: result.URL.ExpiresAt.Format(time.RFC3339),
+			Existing:  result.Existing,
+		})
+	}
+
+	respondWithJSON(ctx, w, http.StatusOK, response)
+}
+
+// createErrorDetails maps create error to the same title Create responds with,
+// message is empty for internal errors so their details don't leak
+func createErrorDetails(err error) (string, string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidURL):
+		return "Invalid URL", err.Error()
+	case errors.Is(err, service.ErrInvalidTTL):
+		return "Invalid TTL", err.Error()
+	case errors.Is(err, service.ErrInvalidRedirectType):
+		return "Invalid redirect type", err.Error()
+	case errors.Is(err, service.ErrInvalidAlias):
+		return "Invalid alias", err.Error()
+	case errors.Is(err, service.ErrAliasTaken):
+		return "Alias already taken", err.Error()
+	case errors.Is(err, service.ErrInvalidMaxClicks):
+		return "Invalid max clicks", err.Error()
+	case errors.Is(err, service.ErrInvalidPassword):
+		return "Invalid password", err.Error()
+	case errors.Is(err, service.ErrInvalidLabel):
+		return "Invalid label", err.Error()
+	case errors.Is(err, service.ErrInvalidDedupe):
+		return "Invalid dedupe", err.Error()
+	case errors.Is(err, service.ErrInvalidTitle):
+		return "Invalid title", err.Error()
+	case errors.Is(err, service.ErrInvalidTargeting):
+		return "Invalid targeting", err.Error()
+	default:
+		return "Internal server error", ""
+	}
+}
@@ -0,0 +1,196 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"html/template"
+	"math"
+	"net/http"
+	"net/netip"
+	neturl "net/url"
+	"strconv"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/service"
+	"go.uber.org/zap"
+)
+
+// pageTimeFormat is how dates are shown to visitors
+const pageTimeFormat = "Jan 2, 2006 15:04 MST"
+
+//go:embed templates/*.html
+var templatesFS embed.FS
+
+// pages maps page name to template set, each page is rendered inside the shared layout
+var pages = map[string]*template.Template{
+	"error":        parsePage("error"),
+	"password":     parsePage("password"),
+	"preview":      parsePage("preview"),
+	"interstitial": parsePage("interstitial"),
+}
+
+func parsePage(name string) *template.Template {
+	return template.Must(template.ParseFS(templatesFS, "templates/layout.html", "templates/"+name+".html"))
+}
+
+// visitorFromRequest collects client details, RemoteAddr is already resolved by RealIP middleware.
+// Country is taken from countryHeader of trusted proxy, empty header name skips it.
+func visitorFromRequest(r *http.Request, countryHeader string) *domain.Visitor {
+	visitor := &domain.Visitor{
+		UserAgent: r.UserAgent(),
+		Referrer:  r.Referer(),
+	}
+	if countryHeader != "" {
+		visitor.Country = r.Header.Get(countryHeader)
+	}
+
+	if addrPort, err := netip.ParseAddrPort(r.RemoteAddr); err == nil {
+		visitor.IP = addrPort.Addr()
+	} else if addr, err := netip.ParseAddr(r.RemoteAddr); err == nil {
+		visitor.IP = addr
+	}
+
+	if uid := r.Header.Get("X-User-Id"); uid != "" {
+		visitor.UserID = &uid
+	}
+
+	return visitor
+}
+
+// errorPage is data for the error template
+type errorPage struct {
+	Title   string
+	Message string
+}
+
+// renderPage is a helper function for consistent HTML responses
+func renderPage(ctx context.Context, w http.ResponseWriter, statusCode int, name string, data interface{}) {
+	// Render into buffer first so template errors don't produce half-written pages
+	var buf bytes.Buffer
+	if err := pages[name].ExecuteTemplate(&buf, "layout", data); err != nil {
+		logger.AppLogErrorCtx(ctx, "Failed to render page", zap.String("page", name), zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(statusCode)
+	if _, err := buf.WriteTo(w); err != nil {
+		logger.AppLogErrorCtx(ctx, "Failed to write page", zap.String("page", name), zap.Error(err))
+	}
+}
+
+// passwordPage is data for the password prompt template
+type passwordPage struct {
+	Title string
+	Error string
+}
+
+// renderPassword asks visitor for link password, wrong is set after a failed attempt
+func renderPassword(ctx context.Context, w http.ResponseWriter, wrong bool) {
+	page := passwordPage{Title: "Password required"}
+	if wrong {
+		page.Error = "Wrong password, please try again."
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	renderPage(ctx, w, http.StatusUnauthorized, "password", page)
+}
+
+// renderError renders error page that is never cached by clients
+func renderError(ctx context.Context, w http.ResponseWriter, statusCode int, title string, message string) {
+	w.Header().Set("Cache-Control", "no-store")
+	renderPage(ctx, w, statusCode, "error", errorPage{Title: title, Message: message})
+}
+
+// renderResolveError renders page explaining why short link can't be followed
+func renderResolveError(ctx context.Context, w http.ResponseWriter, shortCode string, err error) {
+	switch {
+	case errors.Is(err, service.ErrNotFound), errors.Is(err, service.ErrInvalidShortCode):
+		logger.AppLogInfoCtx(ctx, "URL not found",
+			zap.String("short_code", shortCode),
+		)
+		renderError(ctx, w, http.StatusNotFound, "Link not found",
+			"This short link doesn't exist. Check that it was typed correctly.")
+	case errors.Is(err, service.ErrExpired):
+		logger.AppLogInfoCtx(ctx, "URL expired",
+			zap.String("short_code", shortCode),
+		)
+		renderError(ctx, w, http.StatusGone, "Link expired",
+			"This short link has expired and no longer points anywhere.")
+	case errors.Is(err, service.ErrDisabled):
+		renderError(ctx, w, http.StatusGone, "Link disabled",
+			"The owner of this short link has turned it off.")
+	case errors.Is(err, service.ErrURLBlocked):
+		renderError(ctx, w, http.StatusForbidden, "Link disabled",
+			"This short link has been disabled because its destination was reported as unsafe.")
+	case errors.Is(err, service.ErrPasswordRequired):
+		renderPassword(ctx, w, false)
+	case errors.Is(err, service.ErrWrongPassword):
+		renderPassword(ctx, w, true)
+	case errors.Is(err, service.ErrTooManyAttempts):
+		var attemptsErr *service.TooManyAttemptsError
+		if errors.As(err, &attemptsErr) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(attemptsErr.RetryAfter.Seconds()))))
+		}
+		renderError(ctx, w, http.StatusTooManyRequests, "Too many attempts",
+			"This link received too many wrong passwords. Please try again later.")
+	case errors.Is(err, service.ErrConfirmationRequired):
+		var confirmErr *service.ConfirmationRequiredError
+		errors.As(err, &confirmErr)
+		renderInterstitial(ctx, w, confirmErr.URL)
+	default:
+		logger.AppLogErrorCtx(ctx, "Failed to resolve URL",
+			zap.Error(err),
+			zap.String("short_code", shortCode),
+		)
+		renderError(ctx, w, http.StatusInternalServerError, "Something went wrong",
+			"We couldn't open this link right now. Please try again later.")
+	}
+}
+
+// linkPage is data for preview and warning templates, destination is empty for protected
+// and click-limited links, HiddenNote then tells visitor why
+type linkPage struct {
+	Title       string
+	LinkTitle   string
+	Host        string
+	Destination string
+	HiddenNote  string
+	CreatedAt   string
+	ExpiresAt   string
+	ContinueURL string
+}
+
+func newLinkPage(title string, url *domain.URL) linkPage {
+	page := linkPage{
+		Title:       title,
+		LinkTitle:   url.Title,
+		CreatedAt:   url.CreatedAt.UTC().Format(pageTimeFormat),
+		ExpiresAt:   url.ExpiresAt.UTC().Format(pageTimeFormat),
+		ContinueURL: "/" + neturl.PathEscape(url.ShortCode) + "?" + confirmParam + "=1",
+	}
+	// Showing destination of one-time links would hand out invite or download URL without spending a click
+	switch {
+	case url.PasswordHash != "":
+		page.HiddenNote = "This short link is password protected, its destination is shown only after the password is entered."
+	case url.MaxClicks > 0:
+		page.HiddenNote = "This short link can be opened a limited number of times, its destination is shown only after you continue."
+	default:
+		page.Destination = url.OriginalURL
+		if parsed, err := neturl.Parse(url.OriginalURL); err == nil {
+			page.Host = parsed.Hostname()
+		}
+	}
+
+	return page
+}
+
+// renderInterstitial warns visitor where link leads before redirecting
+func renderInterstitial(ctx context.Context, w http.ResponseWriter, url *domain.URL) {
+	w.Header().Set("Cache-Control", "no-store")
+	renderPage(ctx, w, http.StatusOK, "interstitial", newLinkPage("Leaving for another site", url))
+}
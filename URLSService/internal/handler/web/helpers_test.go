@@ -0,0 +1,75 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+)
+
+func TestNewLinkPageHidesDestination(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          domain.URL
+		wantShown    bool
+		wantNoteLike string
+	}{
+		{name: "regular link", url: domain.URL{}, wantShown: true},
+		{name: "password protected", url: domain.URL{PasswordHash: "hash"}, wantNoteLike: "password"},
+		{name: "one-time link", url: domain.URL{MaxClicks: 1}, wantNoteLike: "limited number of times"},
+		{name: "protected and limited", url: domain.URL{PasswordHash: "hash", MaxClicks: 3}, wantNoteLike: "password"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.url.ShortCode = "abc123"
+			tt.url.OriginalURL = "https://downloads.example.com/file?token=secret"
+			tt.url.CreatedAt = time.Now()
+			tt.url.ExpiresAt = time.Now().Add(time.Hour)
+
+			page := newLinkPage("Link preview", &tt.url)
+
+			if tt.wantShown {
+				if page.Destination != tt.url.OriginalURL || page.Host != "downloads.example.com" || page.HiddenNote != "" {
+					t.Fatalf("destination = %q, host = %q, note = %q, want destination shown", page.Destination, page.Host, page.HiddenNote)
+				}
+				return
+			}
+			if page.Destination != "" || page.Host != "" {
+				t.Fatalf("destination = %q, host = %q, want hidden", page.Destination, page.Host)
+			}
+			if !strings.Contains(page.HiddenNote, tt.wantNoteLike) {
+				t.Fatalf("note = %q, want it to mention %q", page.HiddenNote, tt.wantNoteLike)
+			}
+		})
+	}
+}
+
+func TestRenderedPagesHideOneTimeDestination(t *testing.T) {
+	url := &domain.URL{
+		ShortCode:   "abc123",
+		OriginalURL: "https://downloads.example.com/file?token=secret",
+		MaxClicks:   1,
+		CreatedAt:   time.Now(),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+
+	for _, name := range []string{"preview", "interstitial"} {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			renderPage(context.Background(), rec, http.StatusOK, name, newLinkPage("Link", url))
+
+			body := rec.Body.String()
+			if strings.Contains(body, "downloads.example.com") {
+				t.Fatalf("%s page reveals destination of one-time link", name)
+			}
+			if !strings.Contains(body, "shown only after you continue") {
+				t.Fatalf("%s page doesn't explain hidden destination", name)
+			}
+		})
+	}
+}
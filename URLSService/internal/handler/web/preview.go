@@ -0,0 +1,32 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type PreviewHandler struct {
+	service service.URLService
+}
+
+func NewPreviewHandler(service service.URLService) *PreviewHandler {
+	return &PreviewHandler{service: service}
+}
+
+// Preview shows where short link leads without following it, it is not a click
+func (h *PreviewHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	shortCode := chi.URLParam(r, "shortCode")
+
+	// Destination of protected and click-limited links stays hidden, page only tells why
+	url, err := h.service.LookupURL(ctx, shortCode)
+	if err != nil {
+		renderResolveError(ctx, w, shortCode, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	renderPage(ctx, w, http.StatusOK, "preview", newLinkPage("Link preview", url))
+}
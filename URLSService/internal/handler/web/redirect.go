@@ -0,0 +1,123 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/config"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// linkPasswordHeader lets non-browser clients unlock protected links without the form
+	linkPasswordHeader  = "X-Link-Password"
+	maxPasswordFormSize = 4 << 10
+	// confirmParam marks visit coming from the warning page of interstitial links
+	confirmParam = "confirm"
+)
+
+type RedirectHandler struct {
+	service service.URLService
+	config  *config.RedirectConfig
+	geoip   *config.GeoIPConfig
+}
+
+func NewRedirectHandler(service service.URLService, config *config.RedirectConfig, geoip *config.GeoIPConfig) *RedirectHandler {
+	return &RedirectHandler{
+		service: service,
+		config:  config,
+		geoip:   geoip,
+	}
+}
+
+// Redirect resolves short code and redirects client to the original URL
+func (h *RedirectHandler) Redirect(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	shortCode := chi.URLParam(r, "shortCode")
+
+	password := r.Header.Get(linkPasswordHeader)
+	if r.Method == http.MethodPost {
+		r.Body = http.MaxBytesReader(w, r.Body, maxPasswordFormSize)
+		password = r.PostFormValue("password")
+	}
+
+	// Password form and continue button of the warning page are deliberate visits
+	confirmed := r.Method == http.MethodPost || r.URL.Query().Has(confirmParam)
+
+	visitor := visitorFromRequest(r, h.geoip.CountryHeader())
+
+	// HEAD is used by link checkers and unfurlers, it is not a click
+	var url *domain.URL
+	var err error
+	if r.Method == http.MethodHead {
+		url, err = h.service.GetURL(ctx, shortCode, password)
+		if err == nil {
+			url, err = h.service.TargetURL(ctx, url, visitor)
+		}
+		if err == nil && url.Interstitial && !confirmed {
+			err = &service.ConfirmationRequiredError{URL: url}
+		}
+		// Location would let anyone follow click-limited link without spending a click
+		if err == nil && url.MaxClicks > 0 {
+			w.Header().Set("Cache-Control", "no-store")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	} else {
+		url, err = h.service.ResolveURL(ctx, shortCode, password, visitor, confirmed)
+	}
+	if err != nil {
+		renderResolveError(ctx, w, shortCode, err)
+		return
+	}
+
+	status := url.RedirectType
+	if status == 0 {
+		status = h.config.DefaultStatus()
+	}
+	// Unlocked via form, browser must follow with GET instead of re-posting the password
+	if r.Method == http.MethodPost {
+		status = http.StatusSeeOther
+	}
+
+	// Destination of targeted links depends on visitor's client and location
+	if len(url.Targets) > 0 {
+		w.Header().Add("Vary", "User-Agent")
+		if header := h.geoip.CountryHeader(); header != "" {
+			w.Header().Add("Vary", header)
+		}
+	}
+	w.Header().Set("Location", url.OriginalURL)
+	w.Header().Set("Cache-Control", h.cacheControl(url, status))
+	w.WriteHeader(status)
+}
+
+// cacheControl lets clients cache redirect no longer than link lives
+func (h *RedirectHandler) cacheControl(url *domain.URL, status int) string {
+	// Cached redirect would skip the password check or click limit on next visit
+	if url.PasswordHash != "" || url.MaxClicks > 0 {
+		return "no-store"
+	}
+
+	maxAge := time.Until(url.ExpiresAt)
+	if maxAge > h.config.CacheMaxAge() {
+		maxAge = h.config.CacheMaxAge()
+	}
+
+	seconds := int(maxAge / time.Second)
+	if seconds <= 0 {
+		return "no-store"
+	}
+
+	// Temporary redirects may be edited by owner, so only browser can keep them.
+	// Targeted ones are never shared, not every proxy honours Vary.
+	visibility := "private"
+	if len(url.Targets) == 0 && (status == http.StatusMovedPermanently || status == http.StatusPermanentRedirect) {
+		visibility = "public"
+	}
+
+	return visibility + ", max-age=" + strconv.Itoa(seconds)
+}
@@ -0,0 +1,156 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/config"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// fakeURLService serves single link, calls of methods it doesn't implement panic
+type fakeURLService struct {
+	service.URLService
+	url *domain.URL
+	// target replaces destination when set, as a matching targeting rule would
+	target   string
+	resolved int
+}
+
+func (s *fakeURLService) GetURL(ctx context.Context, shortCode string, password string) (*domain.URL, error) {
+	if shortCode != s.url.ShortCode {
+		return nil, service.ErrNotFound
+	}
+	url := *s.url
+	return &url, nil
+}
+
+func (s *fakeURLService) TargetURL(ctx context.Context, url *domain.URL, visitor *domain.Visitor) (*domain.URL, error) {
+	if s.target == "" {
+		return url, nil
+	}
+	targeted := *url
+	targeted.OriginalURL = s.target
+	return &targeted, nil
+}
+
+func (s *fakeURLService) ResolveURL(ctx context.Context, shortCode string, password string, visitor *domain.Visitor, confirmed bool) (*domain.URL, error) {
+	s.resolved++
+	return s.GetURL(ctx, shortCode, password)
+}
+
+func newTestRedirectRouter(t *testing.T, svc service.URLService) http.Handler {
+	t.Helper()
+
+	redirectConfig, err := config.NewRedirectConfigBuilder().Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	geoipConfig, err := config.NewGeoIPConfigBuilder().Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := NewRedirectHandler(svc, redirectConfig, geoipConfig)
+	r := chi.NewRouter()
+	r.Get("/{shortCode}", handler.Redirect)
+	r.Head("/{shortCode}", handler.Redirect)
+	return r
+}
+
+func TestRedirectHead(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxClicks    int
+		wantStatus   int
+		wantLocation string
+	}{
+		{name: "regular link", wantStatus: http.StatusFound, wantLocation: "https://example.com/page"},
+		{name: "one-time link", maxClicks: 1, wantStatus: http.StatusNoContent},
+		{name: "click-limited link", maxClicks: 5, wantStatus: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeURLService{url: &domain.URL{
+				ShortCode:   "abc123",
+				OriginalURL: "https://example.com/page",
+				MaxClicks:   tt.maxClicks,
+				CreatedAt:   time.Now(),
+				ExpiresAt:   time.Now().Add(time.Hour),
+			}}
+			router := newTestRedirectRouter(t, svc)
+
+			// Repeated HEAD must keep giving the same answer, it is never a click
+			for range 3 {
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/abc123", nil))
+
+				if rec.Code != tt.wantStatus {
+					t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+				}
+				if got := rec.Header().Get("Location"); got != tt.wantLocation {
+					t.Fatalf("Location = %q, want %q", got, tt.wantLocation)
+				}
+				if tt.maxClicks > 0 && rec.Header().Get("Cache-Control") != "no-store" {
+					t.Fatalf("Cache-Control = %q, want no-store", rec.Header().Get("Cache-Control"))
+				}
+			}
+			if svc.resolved != 0 {
+				t.Fatalf("HEAD resolved link %d times, want 0", svc.resolved)
+			}
+		})
+	}
+}
+
+func TestRedirectGetOneTimeLink(t *testing.T) {
+	svc := &fakeURLService{url: &domain.URL{
+		ShortCode:   "abc123",
+		OriginalURL: "https://example.com/invite",
+		MaxClicks:   1,
+		CreatedAt:   time.Now(),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}}
+	router := newTestRedirectRouter(t, svc)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/abc123", nil))
+
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != "https://example.com/invite" {
+		t.Fatalf("got %d to %q, want redirect to destination", rec.Code, rec.Header().Get("Location"))
+	}
+	if svc.resolved != 1 {
+		t.Fatalf("GET resolved link %d times, want 1", svc.resolved)
+	}
+}
+
+func TestRedirectHeadInterstitialShowsTarget(t *testing.T) {
+	svc := &fakeURLService{
+		url: &domain.URL{
+			ShortCode:    "abc123",
+			OriginalURL:  "https://example.com/download",
+			Interstitial: true,
+			CreatedAt:    time.Now(),
+			ExpiresAt:    time.Now().Add(time.Hour),
+		},
+		target: "https://apps.apple.com/app/id1",
+	}
+	router := newTestRedirectRouter(t, svc)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/abc123", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want warning page", rec.Code)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "apps.apple.com") || strings.Contains(body, "example.com/download") {
+		t.Fatalf("warning page doesn't name targeted destination: %s", body)
+	}
+}
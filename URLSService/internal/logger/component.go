@@ -12,6 +12,7 @@ const (
 	ComponentPostgres Component = "POSTGRES"
 	ComponentRedis    Component = "REDIS"
 	ComponentApp      Component = "APP"
+	ComponentSecurity Component = "SECURITY"
 )
 
 // No context component loggers
@@ -28,6 +29,10 @@ func App() *zap.Logger {
 	return L().Named(string(ComponentApp))
 }
 
+func Security() *zap.Logger {
+	return L().Named(string(ComponentSecurity))
+}
+
 // Postgres loggers
 
 func PgLogDebugCtx(ctx context.Context, msg string, fields ...zap.Field) {
@@ -195,3 +200,59 @@ func AppLogError(msg string, fields ...zap.Field) {
 		WithOptions(zap.AddCallerSkip(1)).
 		Error(msg, fields...)
 }
+
+// Security loggers
+
+func SecurityLogDebugCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	FromContext(ctx).
+		Named(string(ComponentSecurity)).
+		WithOptions(zap.AddCallerSkip(1)).
+		Debug(msg, fields...)
+}
+
+func SecurityLogInfoCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	FromContext(ctx).
+		Named(string(ComponentSecurity)).
+		WithOptions(zap.AddCallerSkip(1)).
+		Info(msg, fields...)
+}
+
+func SecurityLogWarnCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	FromContext(ctx).
+		Named(string(ComponentSecurity)).
+		WithOptions(zap.AddCallerSkip(1)).
+		Warn(msg, fields...)
+}
+
+func SecurityLogErrorCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	FromContext(ctx).
+		Named(string(ComponentSecurity)).
+		WithOptions(zap.AddCallerSkip(1)).
+		Error(msg, fields...)
+}
+
+// Non-context versions
+
+func SecurityLogDebug(msg string, fields ...zap.Field) {
+	Security().
+		WithOptions(zap.AddCallerSkip(1)).
+		Debug(msg, fields...)
+}
+
+func SecurityLogInfo(msg string, fields ...zap.Field) {
+	Security().
+		WithOptions(zap.AddCallerSkip(1)).
+		Info(msg, fields...)
+}
+
+func SecurityLogWarn(msg string, fields ...zap.Field) {
+	Security().
+		WithOptions(zap.AddCallerSkip(1)).
+		Warn(msg, fields...)
+}
+
+func SecurityLogError(msg string, fields ...zap.Field) {
+	Security().
+		WithOptions(zap.AddCallerSkip(1)).
+		Error(msg, fields...)
+}
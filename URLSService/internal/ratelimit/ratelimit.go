@@ -0,0 +1,16 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// AttemptLimiter locks a key out after too many attempts within a window
+type AttemptLimiter interface {
+	// Reserve counts attempt for key before it is made, so concurrent attempts can't
+	// all slip under the limit. Positive duration means limit is used up and attempt must not be made.
+	// Window starts with the first attempt.
+	Reserve(ctx context.Context, key string) (time.Duration, error)
+	// Reset forgets attempts of key, e.g. after a successful one
+	Reset(ctx context.Context, key string) error
+}
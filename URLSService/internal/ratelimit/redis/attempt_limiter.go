@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/ratelimit"
+	"github.com/redis/go-redis/v9"
+)
+
+// reserveScript counts attempt and reports lockout in one step, returns 0 when attempt is allowed
+// or milliseconds until the window ends. Counter missing TTL gets the full window.
+var reserveScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+	ttl = tonumber(ARGV[1])
+end
+if count > tonumber(ARGV[2]) then
+	return ttl
+end
+return 0
+`)
+
+type attemptLimiter struct {
+	client      *redis.Client
+	prefix      string
+	maxAttempts int64
+	window      time.Duration
+}
+
+// NewAttemptLimiter creates fixed window limiter, counters live under prefix+key
+func NewAttemptLimiter(client *redis.Client, prefix string, maxAttempts int64, window time.Duration) ratelimit.AttemptLimiter {
+	return &attemptLimiter{
+		client:      client,
+		prefix:      prefix,
+		maxAttempts: maxAttempts,
+		window:      window,
+	}
+}
+
+func (l *attemptLimiter) Reserve(ctx context.Context, key string) (time.Duration, error) {
+	retryAfter, err := reserveScript.Run(ctx, l.client,
+		[]string{l.prefix + key},
+		l.window.Milliseconds(),
+		l.maxAttempts,
+	).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("redis attempts reserve error: %w", err)
+	}
+
+	return time.Duration(retryAfter) * time.Millisecond, nil
+}
+
+func (l *attemptLimiter) Reset(ctx context.Context, key string) error {
+	if err := l.client.Del(ctx, l.prefix+key).Err(); err != nil {
+		return fmt.Errorf("redis attempts reset error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+)
+
+type BlocklistRepository interface {
+	GetRules(ctx context.Context) ([]domain.BlockRule, error)
+}
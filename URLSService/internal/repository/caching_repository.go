@@ -40,6 +40,27 @@ func (r *cachingRepository) Create(ctx context.Context, url *domain.URL) error {
 	return nil
 }
 
+func (r *cachingRepository) CreateBatch(ctx context.Context, urls []*domain.URL) (map[string]bool, error) {
+	inserted, err := r.repo.CreateBatch(ctx, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	created := make([]*domain.URL, 0, len(inserted))
+	for _, url := range urls {
+		if inserted[url.ShortCode] {
+			created = append(created, url)
+		}
+	}
+
+	// Rows are already stored, cold cache only costs a database read later
+	if err = r.cache.SetMany(ctx, created); err != nil {
+		logger.RedisLogErrorCtx(ctx, "Failed to warm cache:", zap.Error(err))
+	}
+
+	return inserted, nil
+}
+
 func (r *cachingRepository) GetByShortCode(ctx context.Context, shortCode string) (*domain.URL, error) {
 	url, err := r.cache.Get(ctx, shortCode)
 	if err == nil {
@@ -49,15 +70,23 @@ func (r *cachingRepository) GetByShortCode(ctx context.Context, shortCode string
 		logger.RedisLogInfoCtx(ctx, "Key not found, get negative cache")
 		return nil, ErrNotFound
 	}
+	if errors.Is(err, redis.ErrExpiredCached) {
+		logger.RedisLogInfoCtx(ctx, "Key expired, get negative cache")
+		return nil, ErrExpired
+	}
 	if !errors.Is(err, redis.ErrCacheMiss) {
 		logger.RedisLogErrorCtx(ctx, "Cache error:", zap.Error(err))
 	}
 
 	url, err = r.repo.GetByShortCode(ctx, shortCode)
 	if err != nil {
-		if errors.Is(err, ErrNotFound) {
+		switch {
+		case errors.Is(err, ErrNotFound):
 			logger.RedisLogInfoCtx(ctx, "Key not found, set negative cache")
 			_ = r.cache.SetNegativeCache(ctx, shortCode)
+		case errors.Is(err, ErrExpired):
+			logger.RedisLogInfoCtx(ctx, "Key expired, set negative cache")
+			_ = r.cache.SetExpiredCache(ctx, shortCode)
 		}
 		return nil, err
 	}
@@ -72,8 +101,16 @@ func (r *cachingRepository) GetByShortCode(ctx context.Context, shortCode string
 	return url, nil
 }
 
-func (r *cachingRepository) GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*domain.URL, error) {
-	return r.repo.GetByUserID(ctx, userID, limit, offset)
+func (r *cachingRepository) GetByShortCodeAndUserID(ctx context.Context, shortCode string, userID string) (*domain.URL, error) {
+	return r.repo.GetByShortCodeAndUserID(ctx, shortCode, userID)
+}
+
+func (r *cachingRepository) ListByUserID(ctx context.Context, query URLListQuery) ([]*domain.URL, error) {
+	return r.repo.ListByUserID(ctx, query)
+}
+
+func (r *cachingRepository) CountByUserID(ctx context.Context, filter URLFilter) (int, error) {
+	return r.repo.CountByUserID(ctx, filter)
 }
 
 func (r *cachingRepository) Delete(ctx context.Context, shortCode string) error {
@@ -101,3 +138,66 @@ func (r *cachingRepository) DeleteByShortCodeAndUserID(ctx context.Context, shor
 
 	return nil
 }
+
+func (r *cachingRepository) ConsumeClick(ctx context.Context, shortCode string) (int, error) {
+	remaining, err := r.repo.ConsumeClick(ctx, shortCode)
+	if err != nil && !errors.Is(err, ErrExpired) {
+		return 0, err
+	}
+
+	// Last click is spent, cached URL must stop resolving right away
+	if err != nil || remaining == 0 {
+		logger.RedisLogInfoCtx(ctx, "Clicks exhausted, set negative cache")
+		if cacheErr := r.cache.SetExpiredCache(ctx, shortCode); cacheErr != nil {
+			logger.RedisLogErrorCtx(ctx, "Failed to evict exhausted URL:", zap.Error(cacheErr))
+		}
+	}
+
+	return remaining, err
+}
+
+func (r *cachingRepository) Update(ctx context.Context, url *domain.URL, expectedVersion int) (*domain.URL, error) {
+	updated, err := r.repo.Update(ctx, url, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	// Drops both URL and negative entries, an extended link may be cached as expired
+	if err = r.cache.Delete(ctx, url.ShortCode); err != nil {
+		logger.RedisLogErrorCtx(ctx, "Failed to delete from cache:", zap.Error(err))
+	}
+
+	return updated, nil
+}
+
+func (r *cachingRepository) FindLiveByOriginalURLs(ctx context.Context, userID string, originalURLs []string) ([]*domain.URL, error) {
+	return r.repo.FindLiveByOriginalURLs(ctx, userID, originalURLs)
+}
+
+// DeleteExpired leaves cache as is, entries of expired URLs are already gone or marked expired,
+// and a new URL taking a purged short code overwrites them on create
+func (r *cachingRepository) DeleteExpired(ctx context.Context, expiredBefore time.Time, limit int) (int, error) {
+	return r.repo.DeleteExpired(ctx, expiredBefore, limit)
+}
+
+func (r *cachingRepository) Restore(ctx context.Context, shortCode string, userID string) (*domain.URL, error) {
+	restored, err := r.repo.Restore(ctx, shortCode, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Trashed URL may be cached as not found
+	if err = r.cache.Delete(ctx, shortCode); err != nil {
+		logger.RedisLogErrorCtx(ctx, "Failed to delete from cache:", zap.Error(err))
+	}
+
+	return restored, nil
+}
+
+func (r *cachingRepository) DeleteTrashed(ctx context.Context, deletedBefore time.Time, limit int) (int, error) {
+	return r.repo.DeleteTrashed(ctx, deletedBefore, limit)
+}
+
+func (r *cachingRepository) ExistingShortCodes(ctx context.Context, shortCodes []string) (map[string]bool, error) {
+	return r.repo.ExistingShortCodes(ctx, shortCodes)
+}
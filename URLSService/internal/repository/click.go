@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+)
+
+type ClickRepository interface {
+	CreateBatch(ctx context.Context, clicks []*domain.Click) error
+	CountByShortCode(ctx context.Context, shortCode string, since time.Time) (int64, error)
+	GetSeries(ctx context.Context, shortCode string, bucket domain.ClickBucket, from, to time.Time) ([]domain.ClickSeriesPoint, error)
+	GetTopReferrers(ctx context.Context, shortCode string, from, to time.Time, limit int) ([]domain.ClickCount, error)
+	GetTopUserAgents(ctx context.Context, shortCode string, from, to time.Time, limit int) ([]domain.ClickCount, error)
+}
@@ -0,0 +1,66 @@
+package file
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+)
+
+// blocklistRepository reads rules from a text file, one rule per line:
+//
+//	# comment
+//	evil.example            blocks exactly this host
+//	*.evil.example          blocks every subdomain
+//	~^docs\.example/phish   blocks URLs whose host + path match the regex
+//
+// Anything after " #" is kept as the rule reason.
+type blocklistRepository struct {
+	path string
+}
+
+func NewBlocklistRepository(path string) repository.BlocklistRepository {
+	return &blocklistRepository{path: path}
+}
+
+func (repo *blocklistRepository) GetRules(_ context.Context) ([]domain.BlockRule, error) {
+	f, err := os.Open(repo.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blocklist file: %w", err)
+	}
+	defer f.Close()
+
+	var rules []domain.BlockRule
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var reason string
+		if i := strings.Index(line, " #"); i >= 0 {
+			reason = strings.TrimSpace(line[i+2:])
+			line = strings.TrimSpace(line[:i])
+		}
+
+		switch {
+		case strings.HasPrefix(line, "~"):
+			rules = append(rules, domain.BlockRule{Kind: domain.BlockRuleRegex, Pattern: line[1:], Reason: reason})
+		case strings.HasPrefix(line, "*."):
+			rules = append(rules, domain.BlockRule{Kind: domain.BlockRuleWildcard, Pattern: line[2:], Reason: reason})
+		default:
+			rules = append(rules, domain.BlockRule{Kind: domain.BlockRuleDomain, Pattern: line, Reason: reason})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read blocklist file: %w", err)
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+)
+
+// HealthRepository keeps results of destination checks, owner listings read them with URLs
+type HealthRepository interface {
+	// ListDue returns active live URLs whose current destination wasn't checked since checkedBefore,
+	// never checked ones first
+	ListDue(ctx context.Context, checkedBefore time.Time, limit int) ([]*domain.URL, error)
+	// Record stores check of URL's destination and returns consecutive failures including it,
+	// counting starts anew when destination changed since previous check. ErrNotFound means URL is gone.
+	Record(ctx context.Context, shortCode string, health *domain.LinkHealth, failed bool) (int, error)
+	// Get returns latest check of URL's current destination, nil when it wasn't checked yet
+	Get(ctx context.Context, shortCode string) (*domain.LinkHealth, error)
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+)
+
+var ErrLabelExists = errors.New("label already exists")
+
+// LabelRepository manages user's tags and collections, names match case-insensitively
+type LabelRepository interface {
+	// SetURLTags replaces tags of owned URL, missing tags are created
+	SetURLTags(ctx context.Context, shortCode string, userID string, tags []string) error
+	// SetURLCollection moves owned URL to collection, empty name removes it from any
+	SetURLCollection(ctx context.Context, shortCode string, userID string, collection string) error
+	ListLabels(ctx context.Context, kind domain.LabelKind, userID string) ([]domain.Label, error)
+	RenameLabel(ctx context.Context, kind domain.LabelKind, userID string, name string, newName string) error
+	DeleteLabel(ctx context.Context, kind domain.LabelKind, userID string, name string) error
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "context"
+
+// JobLock lets only one replica run a periodic job at a time
+type JobLock interface {
+	// TryRun runs fn while holding the lock, it returns false without running fn
+	// when another replica holds it
+	TryRun(ctx context.Context, fn func(ctx context.Context) error) (bool, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+)
+
+// MetadataRepository keeps details of destination pages, owner listings read them with URLs
+type MetadataRepository interface {
+	// Save replaces metadata of URL, unknown short codes are ignored
+	Save(ctx context.Context, shortCode string, meta *domain.LinkMetadata) error
+	// ListMissing returns live URLs without metadata for their current destination, newest first.
+	// Failed fetches older than retryFailedBefore count as missing.
+	ListMissing(ctx context.Context, retryFailedBefore time.Time, limit int) ([]*domain.URL, error)
+}
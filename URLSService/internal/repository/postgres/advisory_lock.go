@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// Advisory lock keys, one per background job
+const (
+	URLPurgeLockKey  int64 = 0x75726c7370757267 // "urlspurg"
+	MetadataLockKey  int64 = 0x6d65746164617461 // "metadata"
+	LinkCheckLockKey int64 = 0x6c696e6b6368656b // "linkchek"
+	CodePoolLockKey  int64 = 0x636f6465706f6f6c // "codepool"
+)
+
+// advisoryUnlockTimeout bounds unlock, it runs after job context may be cancelled
+const advisoryUnlockTimeout = 5 * time.Second
+
+// advisoryLock is a session level advisory lock held on a dedicated pool connection,
+// it is released by Postgres itself if the replica dies while holding it
+type advisoryLock struct {
+	connPool *pgxpool.Pool
+	key      int64
+}
+
+func NewAdvisoryLock(connPool *pgxpool.Pool, key int64) repository.JobLock {
+	return &advisoryLock{
+		connPool: connPool,
+		key:      key,
+	}
+}
+
+func (l *advisoryLock) TryRun(ctx context.Context, fn func(ctx context.Context) error) (bool, error) {
+	conn, err := l.connPool.Acquire(ctx)
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't acquire connection", zap.Error(err))
+		return false, err
+	}
+
+	var locked bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&locked); err != nil {
+		conn.Release()
+		logger.PgLogErrorCtx(ctx, "Can't take advisory lock", zap.Error(err), zap.Int64("key", l.key))
+		return false, err
+	}
+	if !locked {
+		conn.Release()
+		return false, nil
+	}
+
+	defer func() {
+		unlockCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), advisoryUnlockTimeout)
+		defer cancel()
+
+		if _, err := conn.Exec(unlockCtx, "SELECT pg_advisory_unlock($1)", l.key); err != nil {
+			// Session keeps the lock until it ends, so the connection must not return to pool
+			logger.PgLogErrorCtx(ctx, "Can't release advisory lock, closing connection", zap.Error(err), zap.Int64("key", l.key))
+			_ = conn.Hijack().Close(unlockCtx)
+			return
+		}
+		conn.Release()
+	}()
+
+	return true, fn(ctx)
+}
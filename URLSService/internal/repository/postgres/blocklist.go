@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type blocklistRepository struct {
+	psql         sq.StatementBuilderType
+	connPool     *pgxpool.Pool
+	queryTimeout time.Duration
+}
+
+func NewBlocklistRepository(connPool *pgxpool.Pool, queryTimeout time.Duration) repository.BlocklistRepository {
+	return &blocklistRepository{
+		connPool:     connPool,
+		queryTimeout: queryTimeout,
+		psql:         sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (repo *blocklistRepository) GetRules(ctx context.Context) ([]domain.BlockRule, error) {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	query, args, err := repo.psql.
+		Select("kind", "pattern", "COALESCE(reason, '')").
+		From("blocklist_rules").
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return nil, err
+	}
+
+	rows, err := repo.connPool.Query(ctx, query, args...)
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []domain.BlockRule
+	for rows.Next() {
+		var rule domain.BlockRule
+		if err := rows.Scan(&rule.Kind, &rule.Pattern, &rule.Reason); err != nil {
+			logger.PgLogErrorCtx(ctx, "Can't scan row", zap.Error(err))
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.PgLogErrorCtx(ctx, "Rows error", zap.Error(err))
+		return nil, err
+	}
+
+	return rules, nil
+}
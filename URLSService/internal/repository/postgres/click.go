@@ -0,0 +1,228 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type clickRepository struct {
+	psql         sq.StatementBuilderType
+	connPool     *pgxpool.Pool
+	queryTimeout time.Duration
+}
+
+func NewClickRepository(connPool *pgxpool.Pool, queryTimeout time.Duration) repository.ClickRepository {
+	return &clickRepository{
+		connPool:     connPool,
+		queryTimeout: queryTimeout,
+		psql:         sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (repo *clickRepository) CreateBatch(ctx context.Context, clicks []*domain.Click) error {
+	if len(clicks) == 0 {
+		return nil
+	}
+
+	// Add timeout for query execution
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	copied, err := repo.connPool.CopyFrom(
+		ctx,
+		pgx.Identifier{"clicks"},
+		[]string{"short_code", "clicked_at", "referrer", "user_agent", "ip_address", "user_id"},
+		pgx.CopyFromSlice(len(clicks), func(i int) ([]any, error) {
+			click := clicks[i]
+			return []any{
+				click.ShortCode,
+				click.ClickedAt,
+				nullableString(click.Referrer),
+				nullableString(click.UserAgent),
+				click.IP,
+				click.UserID,
+			}, nil
+		}),
+	)
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't copy clicks", zap.Error(err))
+		return err
+	}
+	logger.PgLogDebugCtx(ctx, "Clicks copied", zap.Int64("rows", copied))
+
+	return nil
+}
+
+// CountByShortCode counts clicks made since given time, so a reused short code
+// doesn't inherit clicks of a deleted link
+func (repo *clickRepository) CountByShortCode(ctx context.Context, shortCode string, since time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	query, args, err := repo.psql.
+		Select("count(*)").
+		From("clicks").
+		Where(sq.Eq{"short_code": shortCode}).
+		Where(sq.GtOrEq{"clicked_at": since}).
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return 0, err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
+	var total int64
+	if err = repo.connPool.QueryRow(ctx, query, args...).Scan(&total); err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (repo *clickRepository) GetSeries(
+	ctx context.Context,
+	shortCode string,
+	bucket domain.ClickBucket,
+	from, to time.Time,
+) ([]domain.ClickSeriesPoint, error) {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	// Buckets are computed in UTC so they line up with the ones filled in by service
+	query, args, err := repo.psql.
+		Select().
+		Column(sq.Expr("date_trunc(?, clicked_at AT TIME ZONE 'UTC') AS bucket", string(bucket))).
+		Column("count(*)").
+		From("clicks").
+		Where(sq.Eq{"short_code": shortCode}).
+		Where(sq.GtOrEq{"clicked_at": from}).
+		Where(sq.Lt{"clicked_at": to}).
+		GroupBy("bucket").
+		OrderBy("bucket").
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return nil, err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
+	rows, err := repo.connPool.Query(ctx, query, args...)
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var series []domain.ClickSeriesPoint
+	for rows.Next() {
+		var point domain.ClickSeriesPoint
+		if err := rows.Scan(&point.Start, &point.Count); err != nil {
+			logger.PgLogErrorCtx(ctx, "Can't scan row", zap.Error(err))
+			return nil, err
+		}
+		series = append(series, point)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.PgLogErrorCtx(ctx, "Rows error", zap.Error(err))
+		return nil, err
+	}
+
+	return series, nil
+}
+
+func (repo *clickRepository) GetTopReferrers(
+	ctx context.Context,
+	shortCode string,
+	from, to time.Time,
+	limit int,
+) ([]domain.ClickCount, error) {
+	return repo.topValues(ctx, "referrer", shortCode, from, to, limit)
+}
+
+func (repo *clickRepository) GetTopUserAgents(
+	ctx context.Context,
+	shortCode string,
+	from, to time.Time,
+	limit int,
+) ([]domain.ClickCount, error) {
+	return repo.topValues(ctx, "user_agent", shortCode, from, to, limit)
+}
+
+// topValues returns most frequent non-null values of column, column must never come from user input
+func (repo *clickRepository) topValues(
+	ctx context.Context,
+	column string,
+	shortCode string,
+	from, to time.Time,
+	limit int,
+) ([]domain.ClickCount, error) {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	query, args, err := repo.psql.
+		Select(column, "count(*) AS clicks").
+		From("clicks").
+		Where(sq.Eq{"short_code": shortCode}).
+		Where(sq.NotEq{column: nil}).
+		Where(sq.GtOrEq{"clicked_at": from}).
+		Where(sq.Lt{"clicked_at": to}).
+		GroupBy(column).
+		OrderBy("clicks DESC", column).
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return nil, err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
+	rows, err := repo.connPool.Query(ctx, query, args...)
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []domain.ClickCount
+	for rows.Next() {
+		var count domain.ClickCount
+		if err := rows.Scan(&count.Value, &count.Count); err != nil {
+			logger.PgLogErrorCtx(ctx, "Can't scan row", zap.Error(err))
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.PgLogErrorCtx(ctx, "Rows error", zap.Error(err))
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// nullableString stores empty strings as NULL
+func nullableString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// nullableInt stores zero as NULL
+func nullableInt(i int) *int {
+	if i == 0 {
+		return nil
+	}
+	return &i
+}
@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// urlHealthJoin attaches latest check of current destination, checks of previous ones are left out
+const urlHealthJoin = "url_health h ON h.url_id = urls.id AND h.source_url = urls.original_url"
+
+// urlHealthColumns select joined check, in healthScan.dest order
+var urlHealthColumns = []string{
+	"COALESCE(h.status_code, 0)",
+	"COALESCE(h.last_error, '')",
+	"COALESCE(h.consecutive_failures, 0)",
+	"h.checked_at",
+}
+
+type healthRepository struct {
+	psql         sq.StatementBuilderType
+	connPool     *pgxpool.Pool
+	queryTimeout time.Duration
+}
+
+func NewHealthRepository(connPool *pgxpool.Pool, queryTimeout time.Duration) repository.HealthRepository {
+	return &healthRepository{
+		connPool:     connPool,
+		queryTimeout: queryTimeout,
+		psql:         sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (repo *healthRepository) ListDue(ctx context.Context, checkedBefore time.Time, limit int) ([]*domain.URL, error) {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	query, args, err := repo.psql.
+		Select(urlColumns...).
+		From("urls").
+		LeftJoin(urlHealthJoin).
+		Where(sq.Eq{"deleted_at": nil, "active": true}).
+		Where(sq.Gt{"expires_at": time.Now()}).
+		Where(sq.Or{sq.Eq{"h.url_id": nil}, sq.Lt{"h.checked_at": checkedBefore}}).
+		OrderBy("h.checked_at NULLS FIRST").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return nil, err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
+	rows, err := repo.connPool.Query(ctx, query, args...)
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var urls []*domain.URL
+	for rows.Next() {
+		url, err := scanURL(rows)
+		if err != nil {
+			logger.PgLogErrorCtx(ctx, "Can't scan row", zap.Error(err))
+			return nil, err
+		}
+		urls = append(urls, url)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.PgLogErrorCtx(ctx, "Rows error", zap.Error(err))
+		return nil, err
+	}
+
+	return urls, nil
+}
+
+func (repo *healthRepository) Record(ctx context.Context, shortCode string, health *domain.LinkHealth, failed bool) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	failures := 0
+	if failed {
+		failures = 1
+	}
+
+	// Values are cast explicitly, parameters of a select list are taken for text otherwise
+	selected := sq.Select("id").
+		Column("?", health.SourceURL).
+		Column("?::integer", nullableInt(health.StatusCode)).
+		Column("?", nullableString(health.Error)).
+		Column("?::integer", failures).
+		Column("?::timestamptz", health.CheckedAt).
+		From("urls").
+		Where(sq.Eq{"short_code": shortCode})
+
+	query, args, err := repo.psql.
+		Insert("url_health").
+		Columns("url_id", "source_url", "status_code", "last_error", "consecutive_failures", "checked_at").
+		Select(selected).
+		Suffix(`ON CONFLICT (url_id) DO UPDATE SET
+			consecutive_failures = CASE
+				WHEN EXCLUDED.consecutive_failures = 0 THEN 0
+				WHEN url_health.source_url = EXCLUDED.source_url THEN url_health.consecutive_failures + 1
+				ELSE 1
+			END,
+			source_url = EXCLUDED.source_url,
+			status_code = EXCLUDED.status_code,
+			last_error = EXCLUDED.last_error,
+			checked_at = EXCLUDED.checked_at
+		RETURNING consecutive_failures`).
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return 0, err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
+	var consecutive int
+	if err := repo.connPool.QueryRow(ctx, query, args...).Scan(&consecutive); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, repository.ErrNotFound
+		}
+		logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+		return 0, err
+	}
+
+	return consecutive, nil
+}
+
+func (repo *healthRepository) Get(ctx context.Context, shortCode string) (*domain.LinkHealth, error) {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	query, args, err := repo.psql.
+		Select("urls.original_url").
+		Columns(urlHealthColumns...).
+		From("urls").
+		LeftJoin(urlHealthJoin).
+		Where(sq.Eq{"short_code": shortCode}).
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return nil, err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
+	var originalURL string
+	var health healthScan
+	if err := repo.connPool.QueryRow(ctx, query, args...).Scan(append([]any{&originalURL}, health.dest()...)...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+		return nil, err
+	}
+
+	return health.health(originalURL), nil
+}
+
+// healthScan holds columns of urlHealthColumns
+type healthScan struct {
+	check     domain.LinkHealth
+	checkedAt *time.Time
+}
+
+func (s *healthScan) dest() []any {
+	return []any{&s.check.StatusCode, &s.check.Error, &s.check.ConsecutiveFailures, &s.checkedAt}
+}
+
+// health returns scanned check, nil when destination wasn't checked
+func (s *healthScan) health(originalURL string) *domain.LinkHealth {
+	if s.checkedAt == nil {
+		return nil
+	}
+
+	health := s.check
+	health.SourceURL = originalURL
+	health.CheckedAt = *s.checkedAt
+	return &health
+}
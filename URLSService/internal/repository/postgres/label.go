@@ -0,0 +1,283 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// labelTables maps label kind to its table
+var labelTables = map[domain.LabelKind]string{
+	domain.LabelTag:        "tags",
+	domain.LabelCollection: "collections",
+}
+
+// querier is satisfied by both pool and transaction
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type labelRepository struct {
+	psql         sq.StatementBuilderType
+	connPool     *pgxpool.Pool
+	queryTimeout time.Duration
+}
+
+func NewLabelRepository(connPool *pgxpool.Pool, queryTimeout time.Duration) repository.LabelRepository {
+	return &labelRepository{
+		connPool:     connPool,
+		queryTimeout: queryTimeout,
+		psql:         sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (repo *labelRepository) SetURLTags(ctx context.Context, shortCode string, userID string, tags []string) error {
+	return repo.inOwnedURL(ctx, shortCode, userID, func(tx pgx.Tx, urlID string) error {
+		return replaceURLTags(ctx, tx, repo.psql, urlID, userID, tags)
+	})
+}
+
+func (repo *labelRepository) SetURLCollection(ctx context.Context, shortCode string, userID string, collection string) error {
+	return repo.inOwnedURL(ctx, shortCode, userID, func(tx pgx.Tx, urlID string) error {
+		return setURLCollection(ctx, tx, repo.psql, urlID, userID, collection)
+	})
+}
+
+// inOwnedURL runs fn in transaction for id of URL owned by user
+func (repo *labelRepository) inOwnedURL(ctx context.Context, shortCode string, userID string, fn func(tx pgx.Tx, urlID string) error) error {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	tx, err := repo.connPool.Begin(ctx)
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't begin transaction", zap.Error(err))
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query, args, err := repo.psql.
+		Select("id").
+		From("urls").
+		Where(sq.Eq{"short_code": shortCode, "user_id": userID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
+	var urlID string
+	if err := tx.QueryRow(ctx, query, args...).Scan(&urlID); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return repository.ErrNotFound
+		default:
+			logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+			return err
+		}
+	}
+
+	if err := fn(tx, urlID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't commit transaction", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (repo *labelRepository) ListLabels(ctx context.Context, kind domain.LabelKind, userID string) ([]domain.Label, error) {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	builder := repo.psql.Select("l.name", "count(u.id)")
+	switch kind {
+	case domain.LabelTag:
+		builder = builder.
+			From("tags l").
+			LeftJoin("url_tags ut ON ut.tag_id = l.id").
+			LeftJoin("urls u ON u.id = ut.url_id AND u.deleted_at IS NULL")
+	default:
+		builder = builder.
+			From("collections l").
+			LeftJoin("urls u ON u.collection_id = l.id AND u.deleted_at IS NULL")
+	}
+
+	query, args, err := builder.
+		Where(sq.Eq{"l.user_id": userID}).
+		GroupBy("l.id", "l.name").
+		OrderBy("lower(l.name)").
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return nil, err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
+	rows, err := repo.connPool.Query(ctx, query, args...)
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	labels := make([]domain.Label, 0)
+	for rows.Next() {
+		var label domain.Label
+		if err := rows.Scan(&label.Name, &label.URLCount); err != nil {
+			logger.PgLogErrorCtx(ctx, "Can't scan row", zap.Error(err))
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.PgLogErrorCtx(ctx, "Rows error", zap.Error(err))
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+func (repo *labelRepository) RenameLabel(ctx context.Context, kind domain.LabelKind, userID string, name string, newName string) error {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	query, args, err := repo.psql.
+		Update(labelTables[kind]).
+		Set("name", newName).
+		Where(sq.Eq{"user_id": userID}).
+		Where(sq.Expr("lower(name) = lower(?)", name)).
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
+	res, err := repo.connPool.Exec(ctx, query, args...)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return repository.ErrLabelExists
+		}
+		logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+func (repo *labelRepository) DeleteLabel(ctx context.Context, kind domain.LabelKind, userID string, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	// Links keep existing, url_tags rows cascade and collection_id is set to NULL
+	query, args, err := repo.psql.
+		Delete(labelTables[kind]).
+		Where(sq.Eq{"user_id": userID}).
+		Where(sq.Expr("lower(name) = lower(?)", name)).
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
+	res, err := repo.connPool.Exec(ctx, query, args...)
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// replaceURLTags sets URL's tags to exactly tags, creating user's tags that don't exist yet
+func replaceURLTags(ctx context.Context, q querier, psql sq.StatementBuilderType, urlID string, userID string, tags []string) error {
+	statements := []sq.Sqlizer{
+		psql.Delete("url_tags").Where(sq.Eq{"url_id": urlID}),
+	}
+
+	if len(tags) > 0 {
+		lowered := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			lowered = append(lowered, strings.ToLower(tag))
+		}
+
+		statements = append(statements,
+			psql.Insert("tags").
+				Columns("user_id", "name").
+				Select(sq.Select().Column("?", userID).Column("unnest(?::text[])", tags)).
+				Suffix("ON CONFLICT (user_id, lower(name)) DO NOTHING"),
+			psql.Insert("url_tags").
+				Columns("url_id", "tag_id").
+				Select(sq.Select().Column("?", urlID).Column("id").From("tags").
+					Where(sq.Eq{"user_id": userID}).
+					Where("lower(name) = ANY(?)", lowered)),
+		)
+	}
+
+	return execAll(ctx, q, statements)
+}
+
+// setURLCollection moves URL to user's collection, creating it when missing; empty name clears it
+func setURLCollection(ctx context.Context, q querier, psql sq.StatementBuilderType, urlID string, userID string, collection string) error {
+	var collectionID sq.Sqlizer = sq.Expr("NULL")
+	if collection != "" {
+		if err := execAll(ctx, q, []sq.Sqlizer{
+			psql.Insert("collections").
+				Columns("user_id", "name").
+				Values(userID, collection).
+				Suffix("ON CONFLICT (user_id, lower(name)) DO NOTHING"),
+		}); err != nil {
+			return err
+		}
+		collectionID = sq.Expr("(SELECT id FROM collections WHERE user_id = ? AND lower(name) = lower(?))", userID, collection)
+	}
+
+	return execAll(ctx, q, []sq.Sqlizer{
+		psql.Update("urls").Set("collection_id", collectionID).Where(sq.Eq{"id": urlID}),
+	})
+}
+
+func execAll(ctx context.Context, q querier, statements []sq.Sqlizer) error {
+	for _, statement := range statements {
+		query, args, err := statement.ToSql()
+		if err != nil {
+			logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+			return err
+		}
+		logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
+		if _, err := q.Exec(ctx, query, args...); err != nil {
+			logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// urlMetadataJoin attaches metadata fetched for current destination, stale rows are left out
+const urlMetadataJoin = "url_metadata m ON m.url_id = urls.id AND m.source_url = urls.original_url"
+
+// urlMetadataColumns select joined metadata, in metadataScan.dest order
+var urlMetadataColumns = []string{
+	"COALESCE(m.page_title, '')",
+	"COALESCE(m.page_description, '')",
+	"COALESCE(m.image_url, '')",
+	"COALESCE(m.site_name, '')",
+	"COALESCE(m.favicon_url, '')",
+	"COALESCE(m.fetch_error, '')",
+	"m.fetched_at",
+}
+
+type metadataRepository struct {
+	psql         sq.StatementBuilderType
+	connPool     *pgxpool.Pool
+	queryTimeout time.Duration
+}
+
+func NewMetadataRepository(connPool *pgxpool.Pool, queryTimeout time.Duration) repository.MetadataRepository {
+	return &metadataRepository{
+		connPool:     connPool,
+		queryTimeout: queryTimeout,
+		psql:         sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (repo *metadataRepository) Save(ctx context.Context, shortCode string, meta *domain.LinkMetadata) error {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	selected := sq.Select("id").
+		Column("?", meta.SourceURL).
+		Column("?", nullableString(meta.Title)).
+		Column("?", nullableString(meta.Description)).
+		Column("?", nullableString(meta.ImageURL)).
+		Column("?", nullableString(meta.SiteName)).
+		Column("?", nullableString(meta.FaviconURL)).
+		Column("?", nullableString(meta.FetchError)).
+		Column("?::timestamptz", meta.FetchedAt).
+		From("urls").
+		Where(sq.Eq{"short_code": shortCode})
+
+	query, args, err := repo.psql.
+		Insert("url_metadata").
+		Columns("url_id", "source_url", "page_title", "page_description", "image_url", "site_name", "favicon_url", "fetch_error", "fetched_at").
+		Select(selected).
+		Suffix(`ON CONFLICT (url_id) DO UPDATE SET
+			source_url = EXCLUDED.source_url,
+			page_title = EXCLUDED.page_title,
+			page_description = EXCLUDED.page_description,
+			image_url = EXCLUDED.image_url,
+			site_name = EXCLUDED.site_name,
+			favicon_url = EXCLUDED.favicon_url,
+			fetch_error = EXCLUDED.fetch_error,
+			fetched_at = EXCLUDED.fetched_at`).
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
+	if _, err := repo.connPool.Exec(ctx, query, args...); err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (repo *metadataRepository) ListMissing(ctx context.Context, retryFailedBefore time.Time, limit int) ([]*domain.URL, error) {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	query, args, err := repo.psql.
+		Select(urlColumns...).
+		From("urls").
+		LeftJoin(urlMetadataJoin).
+		Where(sq.Eq{"deleted_at": nil}).
+		Where(sq.Gt{"expires_at": time.Now()}).
+		Where(sq.Or{
+			sq.Eq{"m.url_id": nil},
+			sq.And{sq.NotEq{"m.fetch_error": nil}, sq.Lt{"m.fetched_at": retryFailedBefore}},
+		}).
+		OrderBy("created_at DESC").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return nil, err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
+	rows, err := repo.connPool.Query(ctx, query, args...)
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var urls []*domain.URL
+	for rows.Next() {
+		url, err := scanURL(rows)
+		if err != nil {
+			logger.PgLogErrorCtx(ctx, "Can't scan row", zap.Error(err))
+			return nil, err
+		}
+		urls = append(urls, url)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.PgLogErrorCtx(ctx, "Rows error", zap.Error(err))
+		return nil, err
+	}
+
+	return urls, nil
+}
+
+// metadataScan holds columns of urlMetadataColumns
+type metadataScan struct {
+	meta      domain.LinkMetadata
+	fetchedAt *time.Time
+}
+
+func (s *metadataScan) dest() []any {
+	return []any{&s.meta.Title, &s.meta.Description, &s.meta.ImageURL, &s.meta.SiteName, &s.meta.FaviconURL, &s.meta.FetchError, &s.fetchedAt}
+}
+
+// metadata returns scanned metadata, nil when URL has none for its destination
+func (s *metadataScan) metadata(url *domain.URL) *domain.LinkMetadata {
+	if s.fetchedAt == nil {
+		return nil
+	}
+
+	meta := s.meta
+	meta.SourceURL = url.OriginalURL
+	meta.FetchedAt = *s.fetchedAt
+	return &meta
+}
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type codeSequence struct {
+	psql         sq.StatementBuilderType
+	connPool     *pgxpool.Pool
+	queryTimeout time.Duration
+}
+
+func NewCodeSequence(connPool *pgxpool.Pool, queryTimeout time.Duration) repository.CodeSequence {
+	return &codeSequence{
+		connPool:     connPool,
+		queryTimeout: queryTimeout,
+		psql:         sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (repo *codeSequence) NextValues(ctx context.Context, n int) ([]int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	query, args, err := repo.psql.
+		Select("nextval('short_code_seq')").
+		From("generate_series(1, " + strconv.Itoa(n) + ")").
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return nil, err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
+	rows, err := repo.connPool.Query(ctx, query, args...)
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't reserve sequence values", zap.Error(err))
+		return nil, err
+	}
+
+	values, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't scan sequence values", zap.Error(err))
+		return nil, err
+	}
+
+	return values, nil
+}
@@ -2,7 +2,11 @@ package postgres
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
@@ -14,6 +18,18 @@ import (
 	"go.uber.org/zap"
 )
 
+// urlInsertColumns is the column set written on create, in urlInsertValues order
+var urlInsertColumns = []string{"short_code", "original_url", "user_id", "redirect_type", "password_hash", "max_clicks", "title", "interstitial", "targets", "expires_at", "created_at"}
+
+// urlColumns is the column set every URL read selects, in scanURL order
+var urlColumns = []string{"short_code", "original_url", "user_id", "redirect_type", "password_hash", "max_clicks", "clicks_used", "active", "version", "expires_at", "created_at", "deleted_at", "title", "interstitial", "targets"}
+
+// urlLabelColumns select owner's tags and collection name, in ListByUserID scan order
+var urlLabelColumns = []string{
+	"(SELECT array_agg(t.name ORDER BY lower(t.name)) FROM url_tags ut JOIN tags t ON t.id = ut.tag_id WHERE ut.url_id = urls.id) AS tags",
+	"(SELECT c.name FROM collections c WHERE c.id = urls.collection_id) AS collection",
+}
+
 type urlRepository struct {
 	psql         sq.StatementBuilderType
 	connPool     *pgxpool.Pool
@@ -34,10 +50,9 @@ func (repo *urlRepository) GetByShortCode(ctx context.Context, shortCode string)
 	defer cancel()
 
 	query, args, err := repo.psql.
-		Select("short_code", "original_url", "user_id", "expires_at", "created_at").
+		Select(urlColumns...).
 		From("urls").
 		Where(sq.Eq{"short_code": shortCode}).
-		Where(sq.Gt{"expires_at": time.Now()}).
 		ToSql()
 	if err != nil {
 		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
@@ -45,15 +60,7 @@ func (repo *urlRepository) GetByShortCode(ctx context.Context, shortCode string)
 	}
 	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
 
-	url := &domain.URL{}
-
-	err = repo.connPool.QueryRow(ctx, query, args...).Scan(
-		&url.ShortCode,
-		&url.OriginalURL,
-		&url.UserID,
-		&url.ExpiresAt,
-		&url.CreatedAt,
-	)
+	url, err := scanURL(repo.connPool.QueryRow(ctx, query, args...))
 	if err != nil {
 		switch {
 		case errors.Is(err, pgx.ErrNoRows):
@@ -65,21 +72,30 @@ func (repo *urlRepository) GetByShortCode(ctx context.Context, shortCode string)
 		}
 	}
 
+	if url.DeletedAt != nil {
+		return nil, repository.ErrNotFound
+	}
+
+	// Expired rows are kept until purged, so they can be told apart from unknown codes
+	if !url.ExpiresAt.After(time.Now()) {
+		return nil, repository.ErrExpired
+	}
+	if url.MaxClicks > 0 && url.ClicksUsed >= url.MaxClicks {
+		return nil, repository.ErrExpired
+	}
+
 	return url, nil
 }
 
-func (repo *urlRepository) GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*domain.URL, error) {
+func (repo *urlRepository) GetByShortCodeAndUserID(ctx context.Context, shortCode string, userID string) (*domain.URL, error) {
+	// Add timeout for query execution
 	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
 	defer cancel()
 
 	query, args, err := repo.psql.
-		Select("short_code", "original_url", "user_id", "expires_at", "created_at").
+		Select(urlColumns...).
 		From("urls").
-		Where(sq.Eq{"user_id": userID}).
-		Where(sq.Gt{"expires_at": time.Now()}).
-		OrderBy("created_at DESC").
-		Limit(uint64(limit)).
-		Offset(uint64(offset)).
+		Where(sq.Eq{"short_code": shortCode}).
 		ToSql()
 	if err != nil {
 		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
@@ -87,6 +103,70 @@ func (repo *urlRepository) GetByUserID(ctx context.Context, userID string, limit
 	}
 	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
 
+	url, err := scanURL(repo.connPool.QueryRow(ctx, query, args...))
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrNotFound
+		default:
+			logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	if url.UserID == nil || *url.UserID != userID {
+		return nil, repository.ErrForbidden
+	}
+	if url.DeletedAt != nil {
+		return nil, repository.ErrNotFound
+	}
+
+	return url, nil
+}
+
+func (repo *urlRepository) ListByUserID(ctx context.Context, listQuery repository.URLListQuery) ([]*domain.URL, error) {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	sortBy := listQuery.SortBy
+	if sortBy == "" {
+		sortBy = repository.URLSortCreatedAt
+	}
+	direction, keysetOp := "DESC", "<"
+	if listQuery.Ascending {
+		direction, keysetOp = "ASC", ">"
+	}
+
+	// short_code breaks ties between links sharing the same timestamp
+	builder := applyURLFilter(
+		repo.psql.Select(urlColumns...).
+			Columns(urlLabelColumns...).
+			Columns(urlMetadataColumns...).
+			Columns(urlHealthColumns...).
+			From("urls").
+			LeftJoin(urlMetadataJoin).
+			LeftJoin(urlHealthJoin),
+		listQuery.URLFilter,
+	).
+		OrderBy(string(sortBy)+" "+direction, "short_code "+direction).
+		Limit(uint64(listQuery.Limit))
+
+	switch {
+	case listQuery.After != nil:
+		builder = builder.Where(
+			sq.Expr("("+string(sortBy)+", short_code) "+keysetOp+" (?, ?)", listQuery.After.SortValue, listQuery.After.ShortCode),
+		)
+	case listQuery.Offset > 0:
+		builder = builder.Offset(uint64(listQuery.Offset))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return nil, err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
 	rows, err := repo.connPool.Query(ctx, query, args...)
 	if err != nil {
 		logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
@@ -96,11 +176,22 @@ func (repo *urlRepository) GetByUserID(ctx context.Context, userID string, limit
 
 	var urls []*domain.URL
 	for rows.Next() {
-		url := &domain.URL{}
-		if err := rows.Scan(&url.ShortCode, &url.OriginalURL, &url.UserID, &url.ExpiresAt, &url.CreatedAt); err != nil {
+		var tags []string
+		var collection *string
+		var meta metadataScan
+		var health healthScan
+		extra := append([]any{&tags, &collection}, meta.dest()...)
+		url, err := scanURL(rows, append(extra, health.dest()...)...)
+		if err != nil {
 			logger.PgLogErrorCtx(ctx, "Can't scan row", zap.Error(err))
 			return nil, err
 		}
+		url.Tags = tags
+		if collection != nil {
+			url.Collection = *collection
+		}
+		url.Metadata = meta.metadata(url)
+		url.Health = health.health(url.OriginalURL)
 		urls = append(urls, url)
 	}
 
@@ -112,6 +203,27 @@ func (repo *urlRepository) GetByUserID(ctx context.Context, userID string, limit
 	return urls, nil
 }
 
+func (repo *urlRepository) CountByUserID(ctx context.Context, filter repository.URLFilter) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	query, args, err := applyURLFilter(repo.psql.Select("count(*)").From("urls"), filter).
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return 0, err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
+	var count int
+	if err := repo.connPool.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+		return 0, err
+	}
+
+	return count, nil
+}
+
 func (repo *urlRepository) Delete(ctx context.Context, shortCode string) error {
 	// Add timeout for query execution
 	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
@@ -147,8 +259,9 @@ func (repo *urlRepository) Create(ctx context.Context, url *domain.URL) error {
 
 	query, args, err := repo.psql.
 		Insert("urls").
-		Columns("short_code", "original_url", "user_id", "expires_at", "created_at").
-		Values(url.ShortCode, url.OriginalURL, url.UserID, url.ExpiresAt, url.CreatedAt).
+		Columns(urlInsertColumns...).
+		Values(urlInsertValues(url)...).
+		Suffix("RETURNING id").
 		ToSql()
 	if err != nil {
 		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
@@ -156,12 +269,90 @@ func (repo *urlRepository) Create(ctx context.Context, url *domain.URL) error {
 	}
 	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
 
-	_, err = repo.connPool.Exec(ctx, query, args...)
+	if !hasLabels(url) {
+		_, err = repo.connPool.Exec(ctx, query, args...)
+		if err != nil {
+			logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+		}
+		return err
+	}
+
+	return repo.inTx(ctx, func(tx pgx.Tx) error {
+		var urlID string
+		if err := tx.QueryRow(ctx, query, args...).Scan(&urlID); err != nil {
+			logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+			return err
+		}
+		return repo.applyLabels(ctx, tx, urlID, url)
+	})
+}
+
+func (repo *urlRepository) CreateBatch(ctx context.Context, urls []*domain.URL) (map[string]bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	builder := repo.psql.
+		Insert("urls").
+		Columns(urlInsertColumns...)
+	labeled := make(map[string]*domain.URL)
+	for _, url := range urls {
+		builder = builder.Values(urlInsertValues(url)...)
+		if hasLabels(url) {
+			labeled[url.ShortCode] = url
+		}
+	}
+
+	// Rows with taken codes are skipped instead of failing the whole statement
+	query, args, err := builder.
+		Suffix("ON CONFLICT (short_code) DO NOTHING RETURNING short_code, id").
+		ToSql()
 	if err != nil {
-		logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return nil, err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Int("rows", len(urls)))
+
+	inserted := make(map[string]bool, len(urls))
+	err = repo.inTx(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+			return err
+		}
+		defer rows.Close()
+
+		urlIDs := make(map[string]string, len(labeled))
+		for rows.Next() {
+			var shortCode, urlID string
+			if err := rows.Scan(&shortCode, &urlID); err != nil {
+				logger.PgLogErrorCtx(ctx, "Can't scan row", zap.Error(err))
+				return err
+			}
+			inserted[shortCode] = true
+			if _, ok := labeled[shortCode]; ok {
+				urlIDs[shortCode] = urlID
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+			return err
+		}
+
+		// Labels are applied only to rows that were actually inserted
+		for shortCode, urlID := range urlIDs {
+			if err := repo.applyLabels(ctx, tx, urlID, labeled[shortCode]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return err
+	return inserted, nil
 }
 
 func (repo *urlRepository) DeleteByShortCodeAndUserID(ctx context.Context, shortCode string, userID string) error {
@@ -169,10 +360,13 @@ func (repo *urlRepository) DeleteByShortCodeAndUserID(ctx context.Context, short
 	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
 	defer cancel()
 
+	// Row is kept until trash purge, so its unique short code can't be taken by someone else
 	query, args, err := repo.psql.
-		Delete("urls").
+		Update("urls").
+		Set("deleted_at", time.Now()).
 		Where(sq.Eq{"short_code": shortCode}).
 		Where(sq.Eq{"user_id": userID}).
+		Where(sq.Eq{"deleted_at": nil}).
 		ToSql()
 	if err != nil {
 		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
@@ -192,3 +386,445 @@ func (repo *urlRepository) DeleteByShortCodeAndUserID(ctx context.Context, short
 
 	return nil
 }
+
+func (repo *urlRepository) Restore(ctx context.Context, shortCode string, userID string) (*domain.URL, error) {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	query, args, err := repo.psql.
+		Update("urls").
+		Set("deleted_at", nil).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"short_code": shortCode, "user_id": userID}).
+		Where(sq.NotEq{"deleted_at": nil}).
+		Suffix("RETURNING " + strings.Join(urlColumns, ", ")).
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return nil, err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
+	restored, err := scanURL(repo.connPool.QueryRow(ctx, query, args...))
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrNotFound
+		default:
+			logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	return restored, nil
+}
+
+func (repo *urlRepository) ConsumeClick(ctx context.Context, shortCode string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	// Conditional update serializes concurrent resolutions on the row lock,
+	// so the last click can be spent only once across all replicas
+	query, args, err := repo.psql.
+		Update("urls").
+		Set("clicks_used", sq.Expr("clicks_used + 1")).
+		Where(sq.Eq{"short_code": shortCode}).
+		Where("clicks_used < max_clicks").
+		Where(sq.Gt{"expires_at": time.Now()}).
+		Where(sq.Eq{"deleted_at": nil}).
+		Suffix("RETURNING max_clicks - clicks_used").
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return 0, err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
+	var remaining int
+	if err := repo.connPool.QueryRow(ctx, query, args...).Scan(&remaining); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return 0, repository.ErrExpired
+		default:
+			logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+			return 0, err
+		}
+	}
+
+	return remaining, nil
+}
+
+func (repo *urlRepository) Update(ctx context.Context, url *domain.URL, expectedVersion int) (*domain.URL, error) {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	query, args, err := repo.psql.
+		Update("urls").
+		Set("original_url", url.OriginalURL).
+		Set("expires_at", url.ExpiresAt).
+		Set("active", !url.Disabled).
+		Set("title", nullableString(url.Title)).
+		Set("interstitial", url.Interstitial).
+		Set("targets", nullableTargets(url.Targets)).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"short_code": url.ShortCode, "user_id": url.UserID, "version": expectedVersion, "deleted_at": nil}).
+		Suffix("RETURNING " + strings.Join(urlColumns, ", ")).
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return nil, err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
+	updated, err := scanURL(repo.connPool.QueryRow(ctx, query, args...))
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrVersionMismatch
+		default:
+			logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	return updated, nil
+}
+
+func (repo *urlRepository) FindLiveByOriginalURLs(ctx context.Context, userID string, originalURLs []string) ([]*domain.URL, error) {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	// Hashes match md5() of idx_urls_user_id_original_url_md5, destination itself rules out collisions
+	hashes := make([]string, 0, len(originalURLs))
+	for _, originalURL := range originalURLs {
+		sum := md5.Sum([]byte(originalURL))
+		hashes = append(hashes, hex.EncodeToString(sum[:]))
+	}
+
+	query, args, err := repo.psql.
+		Select(urlColumns...).
+		From("urls").
+		Where(sq.Eq{"user_id": userID, "deleted_at": nil}).
+		Where("md5(original_url) = ANY(?)", hashes).
+		Where("original_url = ANY(?)", originalURLs).
+		Where(sq.Eq{"active": true, "password_hash": nil, "max_clicks": nil, "interstitial": false, "targets": nil}).
+		Where(sq.Gt{"expires_at": time.Now()}).
+		OrderBy("expires_at DESC").
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return nil, err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Int("urls", len(originalURLs)))
+
+	rows, err := repo.connPool.Query(ctx, query, args...)
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var urls []*domain.URL
+	for rows.Next() {
+		url, err := scanURL(rows)
+		if err != nil {
+			logger.PgLogErrorCtx(ctx, "Can't scan row", zap.Error(err))
+			return nil, err
+		}
+		urls = append(urls, url)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.PgLogErrorCtx(ctx, "Rows error", zap.Error(err))
+		return nil, err
+	}
+
+	return urls, nil
+}
+
+func (repo *urlRepository) ExistingShortCodes(ctx context.Context, shortCodes []string) (map[string]bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	query, args, err := repo.psql.
+		Select("short_code").
+		From("urls").
+		Where("short_code = ANY(?)", shortCodes).
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return nil, err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Int("short_codes", len(shortCodes)))
+
+	rows, err := repo.connPool.Query(ctx, query, args...)
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+		return nil, err
+	}
+
+	taken, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't scan rows", zap.Error(err))
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(taken))
+	for _, shortCode := range taken {
+		existing[shortCode] = true
+	}
+
+	return existing, nil
+}
+
+func (repo *urlRepository) DeleteExpired(ctx context.Context, expiredBefore time.Time, limit int) (int, error) {
+	// Oldest rows first via idx_urls_expires_at
+	return repo.purge(ctx, sq.Lt{"expires_at": expiredBefore}, "expires_at", limit)
+}
+
+func (repo *urlRepository) DeleteTrashed(ctx context.Context, deletedBefore time.Time, limit int) (int, error) {
+	// Oldest rows first via idx_urls_deleted_at
+	return repo.purge(ctx, sq.Lt{"deleted_at": deletedBefore}, "deleted_at", limit)
+}
+
+// purge permanently removes at most limit URLs matching condition in orderBy order
+func (repo *urlRepository) purge(ctx context.Context, condition sq.Sqlizer, orderBy string, limit int) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, repo.queryTimeout)
+	defer cancel()
+
+	// Rows locked by concurrent edits are left for next batch.
+	// Clicks are removed too, otherwise a new link taking the purged code would inherit its stats.
+	selected := sq.Select("id").
+		From("urls").
+		Where(condition).
+		OrderBy(orderBy).
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED")
+	purged := sq.Delete("urls").
+		Where(sq.Expr("id IN (?)", selected)).
+		Suffix("RETURNING short_code")
+
+	query, args, err := repo.psql.
+		Select("count(*)").
+		From("purged").
+		PrefixExpr(sq.Expr(
+			"WITH purged AS (?), purged_clicks AS (DELETE FROM clicks WHERE short_code IN (SELECT short_code FROM purged))",
+			purged,
+		)).
+		ToSql()
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't build query", zap.Error(err))
+		return 0, err
+	}
+	logger.PgLogInfo("Query:", zap.String("query", query), zap.Any("args", args))
+
+	var deleted int
+	if err := repo.connPool.QueryRow(ctx, query, args...).Scan(&deleted); err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't execute query", zap.Error(err))
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+// applyURLFilter adds list filter conditions, all of them are served by indexes
+func applyURLFilter(builder sq.SelectBuilder, filter repository.URLFilter) sq.SelectBuilder {
+	now := time.Now()
+	builder = builder.Where(sq.Eq{"user_id": filter.UserID})
+
+	if filter.Host != "" {
+		// Subdomain match is a suffix one, on reversed host it becomes a prefix
+		// the (user_id, reverse(destination_host)) index can serve
+		reversed := reverseString(filter.Host)
+		builder = builder.Where(sq.Or{
+			sq.Eq{"reverse(destination_host)": reversed},
+			sq.Like{"reverse(destination_host)": escapeLike(reversed) + ".%"},
+		})
+	}
+	if filter.Search != "" {
+		pattern := "%" + escapeLike(filter.Search) + "%"
+		builder = builder.Where(sq.Or{
+			sq.ILike{"original_url": pattern},
+			sq.ILike{"short_code": pattern},
+			sq.ILike{"title": pattern},
+			sq.Expr("EXISTS (SELECT 1 FROM url_metadata um WHERE um.url_id = urls.id AND um.page_title ILIKE ?)", pattern),
+		})
+	}
+
+	if !filter.CreatedAfter.IsZero() {
+		builder = builder.Where(sq.GtOrEq{"created_at": filter.CreatedAfter})
+	}
+	if !filter.CreatedBefore.IsZero() {
+		builder = builder.Where(sq.Lt{"created_at": filter.CreatedBefore})
+	}
+	if !filter.ExpiresAfter.IsZero() {
+		builder = builder.Where(sq.GtOrEq{"expires_at": filter.ExpiresAfter})
+	}
+	if !filter.ExpiresBefore.IsZero() {
+		builder = builder.Where(sq.Lt{"expires_at": filter.ExpiresBefore})
+	}
+
+	// Every listed tag must be attached, labels belong to the same user as the links
+	for _, tag := range filter.Tags {
+		builder = builder.Where(sq.Expr(
+			"EXISTS (SELECT 1 FROM url_tags ut JOIN tags t ON t.id = ut.tag_id WHERE ut.url_id = urls.id AND lower(t.name) = lower(?))", tag,
+		))
+	}
+	if filter.Collection != "" {
+		builder = builder.Where(sq.Expr(
+			"collection_id IN (SELECT id FROM collections WHERE user_id = ? AND lower(name) = lower(?))", filter.UserID, filter.Collection,
+		))
+	}
+
+	if filter.Status == repository.URLStatusTrashed {
+		return builder.Where(sq.NotEq{"deleted_at": nil})
+	}
+	builder = builder.Where(sq.Eq{"deleted_at": nil})
+
+	switch filter.Status {
+	case repository.URLStatusLive:
+		builder = builder.Where(sq.Gt{"expires_at": now})
+	case repository.URLStatusActive:
+		builder = builder.
+			Where(sq.Gt{"expires_at": now}).
+			Where(sq.Eq{"active": true}).
+			Where(sq.Or{sq.Eq{"max_clicks": nil}, sq.Expr("clicks_used < max_clicks")})
+	case repository.URLStatusExpired:
+		builder = builder.Where(sq.Or{sq.LtOrEq{"expires_at": now}, sq.Expr("clicks_used >= max_clicks")})
+	case repository.URLStatusDisabled:
+		builder = builder.
+			Where(sq.Gt{"expires_at": now}).
+			Where(sq.Eq{"active": false})
+	}
+
+	return builder
+}
+
+func hasLabels(url *domain.URL) bool {
+	return len(url.Tags) > 0 || url.Collection != ""
+}
+
+// applyLabels attaches url's tags and collection to freshly inserted row
+func (repo *urlRepository) applyLabels(ctx context.Context, tx pgx.Tx, urlID string, url *domain.URL) error {
+	if url.UserID == nil {
+		return nil
+	}
+	if len(url.Tags) > 0 {
+		if err := replaceURLTags(ctx, tx, repo.psql, urlID, *url.UserID, url.Tags); err != nil {
+			return err
+		}
+	}
+	if url.Collection != "" {
+		return setURLCollection(ctx, tx, repo.psql, urlID, *url.UserID, url.Collection)
+	}
+
+	return nil
+}
+
+// inTx runs fn in transaction committed when fn succeeds
+func (repo *urlRepository) inTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := repo.connPool.Begin(ctx)
+	if err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't begin transaction", zap.Error(err))
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.PgLogErrorCtx(ctx, "Can't commit transaction", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// reverseString reverses by characters like PostgreSQL reverse does
+func reverseString(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// escapeLike makes s match literally inside LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func urlInsertValues(url *domain.URL) []interface{} {
+	return []interface{}{
+		url.ShortCode,
+		url.OriginalURL,
+		url.UserID,
+		url.RedirectType,
+		nullableString(url.PasswordHash),
+		nullableInt(url.MaxClicks),
+		nullableString(url.Title),
+		url.Interstitial,
+		nullableTargets(url.Targets),
+		url.ExpiresAt,
+		url.CreatedAt,
+	}
+}
+
+// scanURL reads a row selected with urlColumns, extra destinations follow them
+func scanURL(row pgx.Row, extra ...any) (*domain.URL, error) {
+	url := &domain.URL{}
+	var passwordHash *string
+	var maxClicks *int
+	var active bool
+	var title *string
+	var targets []byte
+	dest := []any{
+		&url.ShortCode,
+		&url.OriginalURL,
+		&url.UserID,
+		&url.RedirectType,
+		&passwordHash,
+		&maxClicks,
+		&url.ClicksUsed,
+		&active,
+		&url.Version,
+		&url.ExpiresAt,
+		&url.CreatedAt,
+		&url.DeletedAt,
+		&title,
+		&url.Interstitial,
+		&targets,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if passwordHash != nil {
+		url.PasswordHash = *passwordHash
+	}
+	if maxClicks != nil {
+		url.MaxClicks = *maxClicks
+	}
+	if title != nil {
+		url.Title = *title
+	}
+	if targets != nil {
+		if err := json.Unmarshal(targets, &url.Targets); err != nil {
+			return nil, err
+		}
+	}
+	url.Disabled = !active
+
+	return url, nil
+}
+
+// nullableTargets stores links without targeting rules as NULL
+func nullableTargets(targets []domain.TargetRule) []byte {
+	if len(targets) == 0 {
+		return nil
+	}
+	// Rules hold only strings and bools, marshalling them can't fail
+	data, _ := json.Marshal(targets)
+	return data
+}
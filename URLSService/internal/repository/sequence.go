@@ -0,0 +1,9 @@
+package repository
+
+import "context"
+
+// CodeSequence hands out unique increasing numbers for sequence based short codes
+type CodeSequence interface {
+	// NextValues reserves n numbers, numbers are never handed out twice even if unused
+	NextValues(ctx context.Context, n int) ([]int64, error)
+}
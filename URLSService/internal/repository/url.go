@@ -3,19 +3,100 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
 )
 
 var (
 	ErrNotFound  = errors.New("url not found")
+	ErrExpired   = errors.New("url expired")
 	ErrForbidden = errors.New("access denied")
+	// ErrVersionMismatch means URL was changed since it was read
+	ErrVersionMismatch = errors.New("url version mismatch")
 )
 
+// URLStatus filters user's URLs by lifecycle state
+type URLStatus string
+
+const (
+	URLStatusLive     URLStatus = ""         // not expired by time, default
+	URLStatusActive   URLStatus = "active"   // resolves right now
+	URLStatusExpired  URLStatus = "expired"  // out of time or clicks
+	URLStatusDisabled URLStatus = "disabled" // turned off by owner, not expired
+	URLStatusAll      URLStatus = "all"
+	URLStatusTrashed  URLStatus = "trashed" // in owner's trash, other statuses never include these
+)
+
+// URLSortField is column user's URLs can be ordered by
+type URLSortField string
+
+const (
+	URLSortCreatedAt URLSortField = "created_at"
+	URLSortExpiresAt URLSortField = "expires_at"
+	URLSortDeletedAt URLSortField = "deleted_at" // trash only
+)
+
+// URLCursor is keyset position in user's URL list, the last URL of previous page
+type URLCursor struct {
+	SortValue time.Time // value of SortBy column
+	ShortCode string
+}
+
+// URLFilter narrows user's URL list, zero fields are not applied
+type URLFilter struct {
+	UserID        string
+	Host          string // destination host, subdomains included
+	Search        string // case-insensitive substring of destination, short code, title or page title
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	ExpiresAfter  time.Time
+	ExpiresBefore time.Time
+	Status        URLStatus
+	Tags          []string // links carrying all of them
+	Collection    string
+}
+
+// URLListQuery selects a page of filtered user's URLs
+type URLListQuery struct {
+	URLFilter
+	SortBy    URLSortField // created_at when empty
+	Ascending bool
+	Limit     int
+	After     *URLCursor // takes precedence over Offset
+	Offset    int        // Deprecated: skips rows one by one, use After
+}
+
 type URLRepository interface {
 	Create(ctx context.Context, url *domain.URL) error
+	// CreateBatch inserts URLs in one statement skipping taken short codes,
+	// it returns set of short codes that were inserted
+	CreateBatch(ctx context.Context, urls []*domain.URL) (map[string]bool, error)
+	// GetByShortCode and GetByShortCodeAndUserID report trashed URLs as not found
 	GetByShortCode(ctx context.Context, shortCode string) (*domain.URL, error)
-	GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*domain.URL, error)
+	// GetByShortCodeAndUserID returns owned URL even if it has expired
+	GetByShortCodeAndUserID(ctx context.Context, shortCode string, userID string) (*domain.URL, error)
+	ListByUserID(ctx context.Context, query URLListQuery) ([]*domain.URL, error)
+	CountByUserID(ctx context.Context, filter URLFilter) (int, error)
 	Delete(ctx context.Context, shortCode string) error
+	// DeleteByShortCodeAndUserID moves owned URL to trash, its short code stays taken
 	DeleteByShortCodeAndUserID(ctx context.Context, shortCode string, userID string) error
+	// Restore takes owned URL out of trash and returns it with bumped version
+	Restore(ctx context.Context, shortCode string, userID string) (*domain.URL, error)
+	// ConsumeClick spends one click of a click-limited link and returns clicks left,
+	// ErrExpired means link is out of clicks or time
+	ConsumeClick(ctx context.Context, shortCode string) (int, error)
+	// Update saves editable fields of owned URL if it is still at expectedVersion
+	// and returns stored URL with bumped version
+	Update(ctx context.Context, url *domain.URL, expectedVersion int) (*domain.URL, error)
+	// FindLiveByOriginalURLs returns user's links to given destinations that resolve without
+	// password or click limit right now, longest living first
+	FindLiveByOriginalURLs(ctx context.Context, userID string, originalURLs []string) ([]*domain.URL, error)
+	// DeleteExpired removes at most limit URLs expired before given time, oldest first,
+	// together with their clicks, and returns how many URLs were removed
+	DeleteExpired(ctx context.Context, expiredBefore time.Time, limit int) (int, error)
+	// DeleteTrashed is DeleteExpired for URLs moved to trash before given time
+	DeleteTrashed(ctx context.Context, deletedBefore time.Time, limit int) (int, error)
+	// ExistingShortCodes returns which of given codes are taken, trashed links included
+	ExistingShortCodes(ctx context.Context, shortCodes []string) (map[string]bool, error)
 }
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	minAliasLength = 3
+	maxAliasLength = 32
+)
+
+// aliasPattern matches the same single path segment the ingress routes to redirect handler
+var aliasPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// reservedAliases collide with service and frontend routes, compared case-insensitively
+var reservedAliases = map[string]bool{
+	"api":       true,
+	"auth":      true,
+	"users":     true,
+	"health":    true,
+	"readiness": true,
+	"admin":     true,
+	"login":     true,
+	"logout":    true,
+	"static":    true,
+	"assets":    true,
+	"shorten":   true,
+	"urls":      true,
+	"trash":     true,
+}
+
+func validateAlias(alias string) error {
+	if len(alias) < minAliasLength || len(alias) > maxAliasLength {
+		return fmt.Errorf("%w: length must be between %d and %d characters", ErrInvalidAlias, minAliasLength, maxAliasLength)
+	}
+	if !aliasPattern.MatchString(alias) {
+		return fmt.Errorf("%w: only latin letters, digits, '-' and '_' are allowed", ErrInvalidAlias)
+	}
+	if strings.Trim(alias, "-_") != alias {
+		return fmt.Errorf("%w: can't start or end with '-' or '_'", ErrInvalidAlias)
+	}
+	if reservedAliases[strings.ToLower(alias)] {
+		return fmt.Errorf("%w: %q is reserved", ErrInvalidAlias, alias)
+	}
+
+	return nil
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+)
+
+const (
+	topClickValuesLimit = 10
+	maxSeriesPoints     = 1000
+	maxClickFieldLength = 512
+)
+
+var (
+	ErrInvalidBucket = errors.New("invalid bucket, expected hour, day or week")
+	ErrInvalidRange  = errors.New("invalid time range")
+)
+
+// defaultStatsRange is how far back stats look when no range is requested
+var defaultStatsRange = map[domain.ClickBucket]time.Duration{
+	domain.ClickBucketHour: 48 * time.Hour,
+	domain.ClickBucketDay:  30 * 24 * time.Hour,
+	domain.ClickBucketWeek: 26 * 7 * 24 * time.Hour,
+}
+
+type AnalyticsService interface {
+	// GetStats returns click statistics for URL owned by userID, zero from/to select default range
+	GetStats(ctx context.Context, shortCode string, userID string, bucket domain.ClickBucket, from, to time.Time) (*domain.ClickStats, error)
+}
+
+type analyticsService struct {
+	urls   repository.URLRepository
+	clicks repository.ClickRepository
+}
+
+func NewAnalyticsService(urls repository.URLRepository, clicks repository.ClickRepository) AnalyticsService {
+	return &analyticsService{
+		urls:   urls,
+		clicks: clicks,
+	}
+}
+
+func (s *analyticsService) GetStats(
+	ctx context.Context,
+	shortCode string,
+	userID string,
+	bucket domain.ClickBucket,
+	from, to time.Time,
+) (*domain.ClickStats, error) {
+	if shortCode == "" {
+		return nil, ErrInvalidShortCode
+	}
+	if userID == "" {
+		return nil, ErrForbidden
+	}
+
+	if bucket == "" {
+		bucket = domain.ClickBucketDay
+	}
+	rangeSize, ok := defaultStatsRange[bucket]
+	if !ok {
+		return nil, ErrInvalidBucket
+	}
+
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-rangeSize)
+	}
+	from = truncateToBucket(from, bucket)
+	if !from.Before(to) || len(bucketStarts(from, to, bucket)) > maxSeriesPoints {
+		return nil, ErrInvalidRange
+	}
+
+	url, err := s.urls.GetByShortCodeAndUserID(ctx, shortCode, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, ErrNotFound
+		case errors.Is(err, repository.ErrForbidden):
+			return nil, ErrForbidden
+		default:
+			return nil, err
+		}
+	}
+
+	// Clicks are keyed by short code only, codes of deleted links can be reused,
+	// so everything before the link was created belongs to a previous owner
+	queryFrom := from
+	if url.CreatedAt.After(queryFrom) {
+		queryFrom = url.CreatedAt
+	}
+
+	total, err := s.clicks.CountByShortCode(ctx, shortCode, url.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := s.clicks.GetSeries(ctx, shortCode, bucket, queryFrom, to)
+	if err != nil {
+		return nil, err
+	}
+
+	referrers, err := s.clicks.GetTopReferrers(ctx, shortCode, queryFrom, to, topClickValuesLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	userAgents, err := s.clicks.GetTopUserAgents(ctx, shortCode, queryFrom, to, topClickValuesLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ClickStats{
+		ShortCode:     shortCode,
+		Total:         total,
+		Bucket:        bucket,
+		From:          from,
+		To:            to,
+		Series:        fillSeries(series, from, to, bucket),
+		TopReferrers:  referrers,
+		TopUserAgents: userAgents,
+	}, nil
+}
+
+// fillSeries adds zero points for buckets without clicks so series is continuous
+func fillSeries(points []domain.ClickSeriesPoint, from, to time.Time, bucket domain.ClickBucket) []domain.ClickSeriesPoint {
+	counts := make(map[int64]int64, len(points))
+	for _, point := range points {
+		counts[point.Start.Unix()] = point.Count
+	}
+
+	starts := bucketStarts(from, to, bucket)
+	series := make([]domain.ClickSeriesPoint, 0, len(starts))
+	for _, start := range starts {
+		series = append(series, domain.ClickSeriesPoint{Start: start, Count: counts[start.Unix()]})
+	}
+
+	return series
+}
+
+func bucketStarts(from, to time.Time, bucket domain.ClickBucket) []time.Time {
+	var starts []time.Time
+	for start := truncateToBucket(from, bucket); start.Before(to); start = nextBucket(start, bucket) {
+		starts = append(starts, start)
+		if len(starts) > maxSeriesPoints {
+			break
+		}
+	}
+
+	return starts
+}
+
+// truncateToBucket mirrors PostgreSQL date_trunc in UTC, weeks start on Monday
+func truncateToBucket(t time.Time, bucket domain.ClickBucket) time.Time {
+	t = t.UTC()
+	switch bucket {
+	case domain.ClickBucketHour:
+		return t.Truncate(time.Hour)
+	case domain.ClickBucketWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func nextBucket(t time.Time, bucket domain.ClickBucket) time.Time {
+	switch bucket {
+	case domain.ClickBucketHour:
+		return t.Add(time.Hour)
+	case domain.ClickBucketWeek:
+		return t.AddDate(0, 0, 7)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// newClick builds click event from visitor, keeping only anonymized IP
+func newClick(shortCode string, visitor *domain.Visitor) *domain.Click {
+	return &domain.Click{
+		ShortCode: shortCode,
+		ClickedAt: time.Now(),
+		Referrer:  sanitizeClickField(visitor.Referrer),
+		UserAgent: sanitizeClickField(visitor.UserAgent),
+		IP:        anonymizeIP(visitor.IP),
+		UserID:    visitor.UserID,
+	}
+}
+
+// anonymizeIP zeroes host part: last octet for IPv4, last 80 bits for IPv6
+func anonymizeIP(ip netip.Addr) netip.Addr {
+	if !ip.IsValid() {
+		return netip.Addr{}
+	}
+
+	ip = ip.Unmap()
+	bits := 48
+	if ip.Is4() {
+		bits = 24
+	}
+
+	prefix, err := ip.Prefix(bits)
+	if err != nil {
+		return netip.Addr{}
+	}
+
+	return prefix.Addr()
+}
+
+// sanitizeClickField caps header value and drops bytes PostgreSQL text can't hold,
+// otherwise a single bad header would fail the whole batch
+func sanitizeClickField(s string) string {
+	if len(s) > maxClickFieldLength {
+		s = s[:maxClickFieldLength]
+	}
+	return strings.ReplaceAll(strings.ToValidUTF8(s, ""), "\x00", "")
+}
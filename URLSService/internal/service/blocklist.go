@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+	"go.uber.org/zap"
+	"golang.org/x/net/idna"
+)
+
+const blocklistReloadTimeout = 10 * time.Second
+
+var ErrURLBlocked = fmt.Errorf("%w: destination is blocked", ErrInvalidURL)
+
+// Blocklist rejects destinations matching abuse rules, rules are swapped atomically on reload
+type Blocklist struct {
+	repo     repository.BlocklistRepository
+	interval time.Duration
+	rules    atomic.Pointer[blockRuleSet]
+}
+
+type blockRuleSet struct {
+	domains   map[string]domain.BlockRule
+	wildcards map[string]domain.BlockRule
+	regexes   []regexBlockRule
+}
+
+type regexBlockRule struct {
+	re   *regexp.Regexp
+	rule domain.BlockRule
+}
+
+// NewBlocklist creates blocklist, nil repo disables it.
+// Rules are reloaded on SIGHUP and, if interval is positive, periodically.
+func NewBlocklist(repo repository.BlocklistRepository, interval time.Duration) *Blocklist {
+	b := &Blocklist{
+		repo:     repo,
+		interval: interval,
+	}
+	b.rules.Store(compileBlockRules(nil))
+
+	return b
+}
+
+// Reload replaces rules with fresh ones from repository, old rules stay on error
+func (b *Blocklist) Reload(ctx context.Context) error {
+	if b.repo == nil {
+		return nil
+	}
+
+	rules, err := b.repo.GetRules(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load blocklist rules: %w", err)
+	}
+
+	set := compileBlockRules(rules)
+	b.rules.Store(set)
+	logger.SecurityLogInfo("Blocklist loaded",
+		zap.Int("domains", len(set.domains)),
+		zap.Int("wildcards", len(set.wildcards)),
+		zap.Int("regexes", len(set.regexes)),
+	)
+
+	return nil
+}
+
+// Start reloads rules on SIGHUP and every interval until ctx is canceled
+func (b *Blocklist) Start(ctx context.Context) {
+	if b.repo == nil {
+		return
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if b.interval > 0 {
+		ticker := time.NewTicker(b.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	logger.SecurityLogInfo("Blocklist reloader started", zap.Duration("interval", b.interval))
+
+	for {
+		select {
+		case <-hup:
+			logger.SecurityLogInfo("Received SIGHUP, reloading blocklist")
+			b.reload()
+		case <-tick:
+			b.reload()
+		case <-ctx.Done():
+			logger.SecurityLogInfo("Blocklist reloader stopped")
+			return
+		}
+	}
+}
+
+func (b *Blocklist) reload() {
+	ctx, cancel := context.WithTimeout(context.Background(), blocklistReloadTimeout)
+	defer cancel()
+
+	if err := b.Reload(ctx); err != nil {
+		logger.SecurityLogError("Blocklist reload failed, keeping previous rules", zap.Error(err))
+	}
+}
+
+// Check returns ErrURLBlocked if destination matches any rule
+func (b *Blocklist) Check(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil
+	}
+
+	// Links created before validation existed may hold non-normalized hosts
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if ascii, err := idna.Lookup.ToASCII(host); err == nil {
+		host = ascii
+	}
+
+	rule := b.rules.Load().match(host, u.Path)
+	if rule == nil {
+		return nil
+	}
+
+	logger.SecurityLogWarnCtx(ctx, "Blocked destination",
+		zap.String("url", rawURL),
+		zap.String("rule_kind", string(rule.Kind)),
+		zap.String("rule_pattern", rule.Pattern),
+		zap.String("rule_reason", rule.Reason),
+	)
+
+	return ErrURLBlocked
+}
+
+func (s *blockRuleSet) match(host, path string) *domain.BlockRule {
+	if rule, ok := s.domains[host]; ok {
+		return &rule
+	}
+
+	// Walk parent domains: a.b.evil.example -> b.evil.example -> evil.example -> example
+	for parent := host; ; {
+		i := strings.IndexByte(parent, '.')
+		if i < 0 {
+			break
+		}
+		parent = parent[i+1:]
+		if rule, ok := s.wildcards[parent]; ok {
+			return &rule
+		}
+	}
+
+	target := host + path
+	for _, r := range s.regexes {
+		if r.re.MatchString(target) {
+			return &r.rule
+		}
+	}
+
+	return nil
+}
+
+// compileBlockRules normalizes domain patterns and compiles regexes, invalid rules are skipped
+func compileBlockRules(rules []domain.BlockRule) *blockRuleSet {
+	set := &blockRuleSet{
+		domains:   make(map[string]domain.BlockRule),
+		wildcards: make(map[string]domain.BlockRule),
+	}
+
+	for _, rule := range rules {
+		switch rule.Kind {
+		case domain.BlockRuleDomain, domain.BlockRuleWildcard:
+			pattern := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(rule.Pattern)), ".")
+			ascii, err := idna.Lookup.ToASCII(pattern)
+			if err != nil || ascii == "" {
+				logger.SecurityLogWarn("Skipping invalid blocklist domain", zap.String("pattern", rule.Pattern))
+				continue
+			}
+			if rule.Kind == domain.BlockRuleDomain {
+				set.domains[ascii] = rule
+			} else {
+				set.wildcards[ascii] = rule
+			}
+		case domain.BlockRuleRegex:
+			re, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				logger.SecurityLogWarn("Skipping invalid blocklist regex", zap.String("pattern", rule.Pattern), zap.Error(err))
+				continue
+			}
+			set.regexes = append(set.regexes, regexBlockRule{re: re, rule: rule})
+		default:
+			logger.SecurityLogWarn("Skipping blocklist rule of unknown kind", zap.String("kind", string(rule.Kind)))
+		}
+	}
+
+	return set
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/stream"
+	"go.uber.org/zap"
+)
+
+const (
+	clickWriteTimeout     = 30 * time.Second
+	clickRetryDelay       = 1 * time.Second
+	idleConsumerThreshold = 24 * time.Hour
+)
+
+// ClickConsumer moves clicks from the stream into PostgreSQL.
+// Entries are acknowledged only after they are written, so events read but not
+// written before a crash stay pending and get re-delivered (at-least-once).
+type ClickConsumer struct {
+	stream        stream.ClickStream
+	repo          repository.ClickRepository
+	batchSize     int64
+	blockTimeout  time.Duration
+	claimIdle     time.Duration
+	claimInterval time.Duration
+}
+
+// NewClickConsumer creates a new click stream consumer
+func NewClickConsumer(
+	stream stream.ClickStream,
+	repo repository.ClickRepository,
+	batchSize int64,
+	blockTimeout time.Duration,
+	claimIdle time.Duration,
+	claimInterval time.Duration,
+) *ClickConsumer {
+	return &ClickConsumer{
+		stream:        stream,
+		repo:          repo,
+		batchSize:     batchSize,
+		blockTimeout:  blockTimeout,
+		claimIdle:     claimIdle,
+		claimInterval: claimInterval,
+	}
+}
+
+// Start consumes the stream until ctx is canceled
+func (c *ClickConsumer) Start(ctx context.Context) {
+	logger.RedisLogInfo("Click consumer started",
+		zap.Int64("batch_size", c.batchSize),
+		zap.Duration("claim_idle", c.claimIdle),
+	)
+
+	for ctx.Err() == nil {
+		if err := c.stream.EnsureGroup(ctx); err != nil {
+			logger.RedisLogError("Failed to create click consumer group", zap.Error(err))
+			c.sleep(ctx, clickRetryDelay)
+			continue
+		}
+		break
+	}
+
+	// Entries left over from previous run of this consumer go first
+	c.drainPending(ctx)
+
+	lastClaim := time.Now()
+	for ctx.Err() == nil {
+		if time.Since(lastClaim) >= c.claimInterval {
+			c.reclaim(ctx)
+			lastClaim = time.Now()
+		}
+
+		messages, err := c.stream.ReadNew(ctx, c.batchSize, c.blockTimeout)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.RedisLogError("Failed to read click stream", zap.Error(err))
+				c.sleep(ctx, clickRetryDelay)
+			}
+			continue
+		}
+
+		if !c.process(messages) {
+			c.sleep(ctx, clickRetryDelay)
+		}
+	}
+
+	logger.RedisLogInfo("Click consumer stopped")
+}
+
+// Lag reports consumer group backlog for readiness output
+func (c *ClickConsumer) Lag(ctx context.Context) (*stream.GroupLag, error) {
+	return c.stream.Lag(ctx)
+}
+
+func (c *ClickConsumer) drainPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		messages, err := c.stream.ReadPending(ctx, c.batchSize)
+		if err != nil {
+			logger.RedisLogError("Failed to read pending clicks", zap.Error(err))
+			return
+		}
+		if len(messages) == 0 || !c.process(messages) {
+			return
+		}
+	}
+}
+
+// reclaim takes over entries abandoned by dead consumers, including own failed writes
+func (c *ClickConsumer) reclaim(ctx context.Context) {
+	messages, err := c.stream.ClaimIdle(ctx, c.claimIdle, c.batchSize)
+	if err != nil {
+		logger.RedisLogError("Failed to claim idle clicks", zap.Error(err))
+		return
+	}
+	if len(messages) > 0 {
+		logger.RedisLogInfo("Claimed idle clicks", zap.Int("count", len(messages)))
+		c.process(messages)
+	}
+
+	removed, err := c.stream.RemoveIdleConsumers(ctx, idleConsumerThreshold)
+	if err != nil {
+		logger.RedisLogWarn("Failed to remove idle consumers", zap.Error(err))
+	} else if removed > 0 {
+		logger.RedisLogInfo("Removed idle click consumers", zap.Int("count", removed))
+	}
+}
+
+// process writes batch and acknowledges it, returns false if batch stays pending
+func (c *ClickConsumer) process(messages []stream.ClickMessage) bool {
+	if len(messages) == 0 {
+		return true
+	}
+
+	ids := make([]string, 0, len(messages))
+	clicks := make([]*domain.Click, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+		if message.Click == nil {
+			logger.RedisLogWarn("Dropping malformed click entry", zap.String("id", message.ID))
+			continue
+		}
+		clicks = append(clicks, message.Click)
+	}
+
+	// Not bound to Start ctx so batch already read is still written on shutdown
+	ctx, cancel := context.WithTimeout(context.Background(), clickWriteTimeout)
+	defer cancel()
+
+	if err := c.repo.CreateBatch(ctx, clicks); err != nil {
+		logger.PgLogError("Failed to write clicks, leaving them pending", zap.Int("count", len(clicks)), zap.Error(err))
+		return false
+	}
+
+	if err := c.stream.Ack(ctx, ids...); err != nil {
+		// Written but not acknowledged, entries will be re-delivered and counted twice
+		logger.RedisLogError("Failed to acknowledge clicks", zap.Int("count", len(ids)), zap.Error(err))
+		return false
+	}
+
+	logger.PgLogDebug("Clicks written", zap.Int("count", len(clicks)))
+	return true
+}
+
+func (c *ClickConsumer) sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-time.After(d):
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+	"go.uber.org/zap"
+)
+
+// maxClicksLimit keeps max_clicks within Postgres INTEGER
+const maxClicksLimit = math.MaxInt32
+
+var ErrInvalidMaxClicks = errors.New("invalid max clicks")
+
+// consumeClick spends one click of a click-limited link. Redis turns away visitors
+// of exhausted links without touching the database, the conditional update in
+// Postgres is the source of truth and catches stale or lost counters.
+func (s *urlService) consumeClick(ctx context.Context, url *domain.URL) error {
+	left, err := s.counter.Take(ctx, url)
+	switch {
+	case err != nil:
+		logger.RedisLogErrorCtx(ctx, "Click counter unavailable, falling back to database",
+			zap.Error(err),
+			zap.String("short_code", url.ShortCode),
+		)
+	case left < 0:
+		return ErrExpired
+	}
+
+	remaining, err := s.repo.ConsumeClick(ctx, url.ShortCode)
+	if err != nil {
+		if errors.Is(err, repository.ErrExpired) {
+			return ErrExpired
+		}
+
+		if refundErr := s.counter.Refund(ctx, url); refundErr != nil {
+			logger.RedisLogErrorCtx(ctx, "Failed to refund click",
+				zap.Error(refundErr),
+				zap.String("short_code", url.ShortCode),
+			)
+		}
+		return err
+	}
+
+	url.ClicksUsed = url.MaxClicks - remaining
+
+	return nil
+}
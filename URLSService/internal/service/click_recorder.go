@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"go.uber.org/zap"
+)
+
+// ClickRecorder stores click events of resolved links, failures never fail the redirect
+type ClickRecorder interface {
+	Record(ctx context.Context, click *domain.Click)
+}
+
+// ClickPublisher hands clicks over to durable storage, e.g. stream.ClickStream
+type ClickPublisher interface {
+	Publish(ctx context.Context, clicks []*domain.Click) error
+}
+
+// StreamClickRecorder publishes every click while its redirect is served, so once redirect
+// is sent the click is in the stream and survives restarts of the service.
+// Clicks the stream doesn't accept within timeout are dropped and counted.
+type StreamClickRecorder struct {
+	publisher ClickPublisher
+	timeout   time.Duration
+	dropped   atomic.Int64
+}
+
+// NewStreamClickRecorder creates recorder, timeout bounds delay a slow stream adds to redirects
+func NewStreamClickRecorder(publisher ClickPublisher, timeout time.Duration) *StreamClickRecorder {
+	return &StreamClickRecorder{
+		publisher: publisher,
+		timeout:   timeout,
+	}
+}
+
+func (r *StreamClickRecorder) Record(ctx context.Context, click *domain.Click) {
+	// Visitor dropping the connection mid-redirect doesn't cancel the write
+	publishCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.timeout)
+	defer cancel()
+
+	if err := r.publisher.Publish(publishCtx, []*domain.Click{click}); err != nil {
+		r.dropped.Add(1)
+		logger.AppLogErrorCtx(ctx, "Failed to publish click, dropping event",
+			zap.String("short_code", click.ShortCode),
+			zap.Error(err),
+		)
+	}
+}
+
+// Dropped returns number of clicks lost since start
+func (r *StreamClickRecorder) Dropped() int64 {
+	return r.dropped.Load()
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// maxFilteredAttempts bounds regeneration when denylist rejects codes in a row
+const maxFilteredAttempts = 100
+
+// separatorReplacer drops word separators, denylist entries may span them
+var separatorReplacer = strings.NewReplacer("-", "", "_", "")
+
+// leetReplacer undoes digit substitutions, so "b00b" is caught by "boob"
+var leetReplacer = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "8", "b")
+
+// filteredCodeGenerator rejects generated codes containing denylisted substrings
+type filteredCodeGenerator struct {
+	source   CodeGenerator
+	denylist []string
+}
+
+// NewFilteredCodeGenerator wraps source, denylist entries are matched case-insensitively
+// against code with separators dropped and digit look-alikes read as letters
+func NewFilteredCodeGenerator(source CodeGenerator, denylist []string) CodeGenerator {
+	normalized := make([]string, 0, len(denylist))
+	for _, entry := range denylist {
+		if entry = strings.ToLower(strings.TrimSpace(entry)); entry != "" {
+			normalized = append(normalized, entry)
+		}
+	}
+
+	return &filteredCodeGenerator{source: source, denylist: normalized}
+}
+
+func (g *filteredCodeGenerator) Generate(ctx context.Context) (string, error) {
+	for attempt := 0; attempt < maxFilteredAttempts; attempt++ {
+		shortCode, err := g.source.Generate(ctx)
+		if err != nil {
+			return "", err
+		}
+		if !g.denied(shortCode) {
+			return shortCode, nil
+		}
+	}
+
+	return "", fmt.Errorf("failed to generate short code passing denylist after %d attempts", maxFilteredAttempts)
+}
+
+func (g *filteredCodeGenerator) Observe(collided bool) {
+	g.source.Observe(collided)
+}
+
+func (g *filteredCodeGenerator) denied(shortCode string) bool {
+	plain := separatorReplacer.Replace(strings.ToLower(shortCode))
+	leet := leetReplacer.Replace(plain)
+
+	for _, entry := range g.denylist {
+		if strings.Contains(plain, entry) || strings.Contains(leet, entry) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LoadCodeDenylist reads denylist file, one substring per line, '#' starts a comment line
+func LoadCodeDenylist(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open code denylist file: %w", err)
+	}
+	defer f.Close()
+
+	var denylist []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		denylist = append(denylist, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read code denylist file: %w", err)
+	}
+
+	return denylist, nil
+}
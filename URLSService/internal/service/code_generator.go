@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"go.uber.org/zap"
+)
+
+// Alphabets of generated codes
+const (
+	AlphabetBase62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// AlphabetUnambiguous is lowercase only and drops look-alikes 0/o, 1/l/i,
+	// codes read from print can be typed in any case
+	AlphabetUnambiguous = "23456789abcdefghjkmnpqrstuvwxyz"
+)
+
+// CodeGenerator produces short codes for links created without alias
+type CodeGenerator interface {
+	Generate(ctx context.Context) (string, error)
+	// Observe reports whether generated code turned out to be taken,
+	// random generators make codes longer once collisions get frequent
+	Observe(collided bool)
+}
+
+// CodeGrowth tells when random codes get one unit longer: after window attempts
+// with collided share above threshold, up to max units
+type CodeGrowth struct {
+	Window    int
+	Threshold float64
+	Max       int
+}
+
+// lengthGrower tracks collision rate of a random generator and grows its length.
+// Length lives in memory, a restarted replica starts short again and regrows if needed.
+type lengthGrower struct {
+	mu         sync.Mutex
+	name       string
+	length     int
+	growth     CodeGrowth
+	attempts   int
+	collisions int
+}
+
+func newLengthGrower(name string, length int, growth CodeGrowth) *lengthGrower {
+	return &lengthGrower{name: name, length: length, growth: growth}
+}
+
+func (g *lengthGrower) Length() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.length
+}
+
+func (g *lengthGrower) Observe(collided bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.attempts++
+	if collided {
+		g.collisions++
+	}
+	if g.attempts < g.growth.Window {
+		return
+	}
+
+	rate := float64(g.collisions) / float64(g.attempts)
+	if rate > g.growth.Threshold && g.length < g.growth.Max {
+		g.length++
+		logger.AppLogWarn("Short code collision rate crossed threshold, growing codes",
+			zap.String("generator", g.name),
+			zap.Float64("collision_rate", rate),
+			zap.Int("length", g.length),
+		)
+	}
+	g.attempts, g.collisions = 0, 0
+}
+
+// randomCodeGenerator makes uniformly random codes of alphabet symbols
+type randomCodeGenerator struct {
+	*lengthGrower
+	alphabet string
+}
+
+func NewRandomCodeGenerator(alphabet string, length int, growth CodeGrowth) CodeGenerator {
+	return &randomCodeGenerator{
+		lengthGrower: newLengthGrower("random", length, growth),
+		alphabet:     alphabet,
+	}
+}
+
+func (g *randomCodeGenerator) Generate(_ context.Context) (string, error) {
+	return randomString(g.alphabet, g.Length())
+}
+
+// randomString picks length symbols of alphabet uniformly, alphabet must be shorter than 256
+func randomString(alphabet string, length int) (string, error) {
+	// Bytes past the largest multiple of alphabet size are dropped, so modulo isn't biased
+	limit := 256 - 256%len(alphabet)
+	code := make([]byte, 0, length)
+	buf := make([]byte, length+length/2)
+
+	for len(code) < length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("failed to generate random bytes %w", err)
+		}
+		for _, b := range buf {
+			if int(b) >= limit {
+				continue
+			}
+			code = append(code, alphabet[int(b)%len(alphabet)])
+			if len(code) == length {
+				break
+			}
+		}
+	}
+
+	return string(code), nil
+}
+
+// randomIndex picks number in [0, n) uniformly
+func randomIndex(n int) (int, error) {
+	var buf [4]byte
+	limit := uint64(1<<32) - uint64(1<<32)%uint64(n)
+	for {
+		if _, err := rand.Read(buf[:]); err != nil {
+			return 0, fmt.Errorf("failed to generate random bytes %w", err)
+		}
+		v := uint64(binary.LittleEndian.Uint32(buf[:]))
+		if v < limit {
+			return int(v % uint64(n)), nil
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+)
+
+const (
+	// sequenceBlockSize is how many sequence numbers one replica reserves per round trip
+	sequenceBlockSize = 100
+
+	feistelHalfBits = 20
+	feistelHalfMask = 1<<feistelHalfBits - 1
+	feistelDomain   = 1 << (2 * feistelHalfBits)
+	feistelRounds   = 4
+)
+
+// sequenceCodeGenerator turns Postgres sequence numbers into codes, neighbouring numbers
+// are scrambled by a keyed permutation so codes don't reveal creation order or volume
+type sequenceCodeGenerator struct {
+	mu       sync.Mutex
+	seq      repository.CodeSequence
+	alphabet string
+	// length is minimal code length, enough to cover the whole permuted domain
+	length  int
+	keys    [feistelRounds]uint64
+	pending []int64
+}
+
+func NewSequenceCodeGenerator(seq repository.CodeSequence, alphabet string, secret string) CodeGenerator {
+	g := &sequenceCodeGenerator{seq: seq, alphabet: alphabet}
+	for span := uint64(1); span < feistelDomain; span *= uint64(len(alphabet)) {
+		g.length++
+	}
+
+	sum := sha256.Sum256([]byte("short-code-sequence:" + secret))
+	for i := range g.keys {
+		g.keys[i] = binary.BigEndian.Uint64(sum[i*8:])
+	}
+
+	return g
+}
+
+func (g *sequenceCodeGenerator) Generate(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.pending) == 0 {
+		values, err := g.seq.NextValues(ctx, sequenceBlockSize)
+		if err != nil {
+			return "", err
+		}
+		if len(values) == 0 {
+			return "", errors.New("short code sequence returned no values")
+		}
+		g.pending = values
+	}
+
+	value := uint64(g.pending[0])
+	g.pending = g.pending[1:]
+
+	return encodeNumber(g.permute(value), g.alphabet, g.length), nil
+}
+
+// Observe is a no-op, sequence numbers never repeat so code length doesn't need to grow
+func (g *sequenceCodeGenerator) Observe(bool) {}
+
+// permute scrambles the low 40 bits with a balanced Feistel network, higher bits
+// are kept as is, so the mapping stays a bijection over all sequence values
+func (g *sequenceCodeGenerator) permute(value uint64) uint64 {
+	high := value - value%feistelDomain
+	left := (value >> feistelHalfBits) & feistelHalfMask
+	right := value & feistelHalfMask
+
+	for _, key := range g.keys {
+		left, right = right, left^(mix64(right^key)&feistelHalfMask)
+	}
+
+	return high | left<<feistelHalfBits | right
+}
+
+// mix64 is splitmix64 finalizer, cheap round function with good avalanche
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// encodeNumber writes value in base of alphabet size, left padded with its first symbol up to minLength
+func encodeNumber(value uint64, alphabet string, minLength int) string {
+	var buf [64]byte
+	base := uint64(len(alphabet))
+	i := len(buf)
+	for value > 0 || len(buf)-i < minLength {
+		i--
+		buf[i] = alphabet[value%base]
+		value /= base
+	}
+
+	return string(buf[i:])
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	_ "embed"
+	"strings"
+)
+
+//go:embed wordlist/words.txt
+var wordlistFile string
+
+// wordlist holds short common words, all lowercase and unambiguous when read aloud
+var wordlist = strings.Fields(wordlistFile)
+
+// wordlistCodeGenerator makes pronounceable codes like "amber-otter-lake"
+type wordlistCodeGenerator struct {
+	*lengthGrower
+}
+
+func NewWordlistCodeGenerator(words int, growth CodeGrowth) CodeGenerator {
+	return &wordlistCodeGenerator{lengthGrower: newLengthGrower("wordlist", words, growth)}
+}
+
+func (g *wordlistCodeGenerator) Generate(_ context.Context) (string, error) {
+	n := g.Length()
+	parts := make([]string, n)
+	for i := range parts {
+		idx, err := randomIndex(len(wordlist))
+		if err != nil {
+			return "", err
+		}
+		parts[i] = wordlist[idx]
+	}
+
+	return strings.Join(parts, "-"), nil
+}
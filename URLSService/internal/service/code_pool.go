@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/cache"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+	"go.uber.org/zap"
+)
+
+// CodePool is CodeGenerator handing out codes pre-generated in background, so create
+// doesn't pay for collisions. Codes are checked against stored links when generated,
+// source generator is used directly when pool runs dry.
+type CodePool struct {
+	store        cache.CodePool
+	repo         repository.URLRepository
+	lock         repository.JobLock
+	source       CodeGenerator
+	capacity     int
+	lowWatermark int
+	batchSize    int
+	interval     time.Duration
+	refill       chan struct{}
+}
+
+func NewCodePool(
+	store cache.CodePool,
+	repo repository.URLRepository,
+	lock repository.JobLock,
+	source CodeGenerator,
+	capacity int,
+	lowWatermark int,
+	batchSize int,
+	interval time.Duration,
+) *CodePool {
+	return &CodePool{
+		store:        store,
+		repo:         repo,
+		lock:         lock,
+		source:       source,
+		capacity:     capacity,
+		lowWatermark: lowWatermark,
+		batchSize:    batchSize,
+		interval:     interval,
+		refill:       make(chan struct{}, 1),
+	}
+}
+
+func (p *CodePool) Generate(ctx context.Context) (string, error) {
+	shortCode, err := p.store.Claim(ctx)
+	if err == nil {
+		return shortCode, nil
+	}
+
+	if errors.Is(err, cache.ErrCodePoolEmpty) {
+		logger.AppLogWarnCtx(ctx, "Short code pool is empty, generating code directly")
+		p.requestRefill()
+	} else {
+		logger.AppLogErrorCtx(ctx, "Failed to claim short code from pool, generating code directly", zap.Error(err))
+	}
+
+	return p.source.Generate(ctx)
+}
+
+// Observe is a no-op, pooled codes collide only with aliases taken meanwhile,
+// collision rate of source is measured by filler instead
+func (p *CodePool) Observe(bool) {}
+
+// Start refills pool on every tick and whenever it runs dry, until ctx is cancelled
+func (p *CodePool) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	logger.AppLogInfo("Short code pool filler started",
+		zap.Int("capacity", p.capacity),
+		zap.Int("low_watermark", p.lowWatermark),
+		zap.Duration("interval", p.interval),
+	)
+
+	// Run immediately on start
+	p.fill(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			p.fill(ctx)
+		case <-p.refill:
+			p.fill(ctx)
+		case <-ctx.Done():
+			logger.AppLogInfo("Short code pool filler stopped")
+			return
+		}
+	}
+}
+
+func (p *CodePool) requestRefill() {
+	select {
+	case p.refill <- struct{}{}:
+	default:
+	}
+}
+
+// fill tops pool up to capacity once it drops below low watermark, one replica at a time
+func (p *CodePool) fill(ctx context.Context) {
+	started := time.Now()
+	added := 0
+
+	ran, err := p.lock.TryRun(ctx, func(ctx context.Context) error {
+		size, err := p.store.Size(ctx)
+		if err != nil {
+			return err
+		}
+		if size >= int64(p.lowWatermark) {
+			return nil
+		}
+
+		for missing := p.capacity - int(size); missing > 0 && ctx.Err() == nil; {
+			n, err := p.addBatch(ctx, min(missing, p.batchSize))
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				// Every candidate was taken, source needs to grow codes first
+				break
+			}
+			added += n
+			missing -= n
+		}
+		return nil
+	})
+
+	switch {
+	case err != nil:
+		logger.AppLogError("Failed to fill short code pool", zap.Int("added", added), zap.Error(err))
+	case !ran:
+		logger.AppLogDebug("Short code pool fill skipped, another replica holds the lock")
+	case added > 0:
+		logger.AppLogInfo("Short code pool filled", zap.Int("added", added), zap.Duration("took", time.Since(started)))
+	}
+}
+
+// addBatch generates up to n codes and pools those not taken by stored links
+func (p *CodePool) addBatch(ctx context.Context, n int) (int, error) {
+	candidates := make([]string, 0, n)
+	seen := make(map[string]bool, n)
+	for len(candidates) < n {
+		shortCode, err := p.source.Generate(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if seen[shortCode] {
+			p.source.Observe(true)
+			continue
+		}
+		seen[shortCode] = true
+		candidates = append(candidates, shortCode)
+	}
+
+	existing, err := p.repo.ExistingShortCodes(ctx, candidates)
+	if err != nil {
+		return 0, err
+	}
+
+	fresh := candidates[:0]
+	for _, shortCode := range candidates {
+		p.source.Observe(existing[shortCode])
+		if !existing[shortCode] {
+			fresh = append(fresh, shortCode)
+		}
+	}
+
+	if err := p.store.Add(ctx, fresh); err != nil {
+		return 0, err
+	}
+
+	return len(fresh), nil
+}
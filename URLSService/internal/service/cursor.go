@@ -0,0 +1,61 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// encodeCursor makes opaque keyset position after url, clients must not parse it.
+// Sort order is part of the cursor, so it can't be replayed against another ordering.
+func encodeCursor(url *domain.URL, query repository.URLListQuery) string {
+	value := url.CreatedAt
+	switch {
+	case query.SortBy == repository.URLSortExpiresAt:
+		value = url.ExpiresAt
+	case query.SortBy == repository.URLSortDeletedAt && url.DeletedAt != nil:
+		value = *url.DeletedAt
+	}
+
+	raw := cursorOrder(query) + ":" + strconv.FormatInt(value.UnixMicro(), 10) + ":" + url.ShortCode
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string, query repository.URLListQuery) (*repository.URLCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	parts := strings.SplitN(string(raw), ":", 3)
+	if len(parts) != 3 || parts[2] == "" {
+		return nil, ErrInvalidCursor
+	}
+	if parts[0] != cursorOrder(query) {
+		return nil, fmt.Errorf("%w: cursor belongs to another sort order", ErrInvalidCursor)
+	}
+	micros, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &repository.URLCursor{
+		SortValue: time.UnixMicro(micros),
+		ShortCode: parts[2],
+	}, nil
+}
+
+func cursorOrder(query repository.URLListQuery) string {
+	if query.Ascending {
+		return string(query.SortBy) + ".asc"
+	}
+	return string(query.SortBy) + ".desc"
+}
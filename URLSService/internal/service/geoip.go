@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/oschwald/maxminddb-golang"
+	"go.uber.org/zap"
+)
+
+// GeoIP finds visitor country in local MaxMind-format database, database is swapped atomically on reload.
+// File is read into memory instead of mapped, so lookups running during reload keep the old one safely.
+type GeoIP struct {
+	path     string
+	interval time.Duration
+	db       atomic.Pointer[geoIPDatabase]
+}
+
+type geoIPDatabase struct {
+	reader  *maxminddb.Reader
+	modTime time.Time
+	size    int64
+}
+
+// geoIPRecord is part of country and city database records targeting needs
+type geoIPRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	RegisteredCountry struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+}
+
+// NewGeoIP creates lookup of database at path, it finds nothing until loaded.
+// Database is reloaded on SIGHUP and, if interval is positive, when file changes.
+func NewGeoIP(path string, interval time.Duration) *GeoIP {
+	return &GeoIP{
+		path:     path,
+		interval: interval,
+	}
+}
+
+// Reload reads database file again, previous database stays on error
+func (g *GeoIP) Reload() error {
+	info, err := os.Stat(g.path)
+	if err != nil {
+		return fmt.Errorf("failed to open geoip database: %w", err)
+	}
+	data, err := os.ReadFile(g.path)
+	if err != nil {
+		return fmt.Errorf("failed to read geoip database: %w", err)
+	}
+	reader, err := maxminddb.FromBytes(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse geoip database: %w", err)
+	}
+
+	g.db.Store(&geoIPDatabase{
+		reader:  reader,
+		modTime: info.ModTime(),
+		size:    info.Size(),
+	})
+	logger.AppLogInfo("GeoIP database loaded",
+		zap.String("path", g.path),
+		zap.String("type", reader.Metadata.DatabaseType),
+		zap.Time("built_at", time.Unix(int64(reader.Metadata.BuildEpoch), 0)),
+	)
+
+	return nil
+}
+
+// Start reloads database on SIGHUP and on file change until ctx is canceled
+func (g *GeoIP) Start(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if g.interval > 0 {
+		ticker := time.NewTicker(g.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	logger.AppLogInfo("GeoIP reloader started", zap.Duration("interval", g.interval))
+
+	for {
+		select {
+		case <-hup:
+			logger.AppLogInfo("Received SIGHUP, reloading geoip database")
+			g.reload()
+		case <-tick:
+			if g.changed() {
+				g.reload()
+			}
+		case <-ctx.Done():
+			logger.AppLogInfo("GeoIP reloader stopped")
+			return
+		}
+	}
+}
+
+// changed reports whether file differs from loaded database, updates are usually whole file replacements
+func (g *GeoIP) changed() bool {
+	info, err := os.Stat(g.path)
+	if err != nil {
+		logger.AppLogError("GeoIP database is unavailable, keeping loaded one", zap.Error(err))
+		return false
+	}
+
+	db := g.db.Load()
+	return db == nil || !info.ModTime().Equal(db.modTime) || info.Size() != db.size
+}
+
+func (g *GeoIP) reload() {
+	if err := g.Reload(); err != nil {
+		logger.AppLogError("GeoIP reload failed, keeping previous database", zap.Error(err))
+	}
+}
+
+// Country returns ISO 3166-1 alpha-2 code of ip's country, empty when unknown
+func (g *GeoIP) Country(ip netip.Addr) string {
+	db := g.db.Load()
+	if db == nil || !ip.IsValid() {
+		return ""
+	}
+
+	return lookupCountry(db.reader, ip)
+}
+
+// lookupCountry falls back to country network is registered in, e.g. for anycast addresses
+func lookupCountry(reader *maxminddb.Reader, ip netip.Addr) string {
+	var record geoIPRecord
+	if err := reader.Lookup(net.IP(ip.Unmap().AsSlice()), &record); err != nil {
+		return ""
+	}
+
+	return normalizeCountry(firstNonEmpty(record.Country.ISOCode, record.RegisteredCountry.ISOCode))
+}
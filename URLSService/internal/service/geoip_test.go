@@ -0,0 +1,109 @@
+package service
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// loadGeoIP copies fixture into a temp dir, so tests may replace it, and loads it
+func loadGeoIP(t *testing.T, fixture string) (*GeoIP, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "geoip.mmdb")
+	copyFixture(t, fixture, path)
+
+	geoIP := NewGeoIP(path, time.Minute)
+	if err := geoIP.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	return geoIP, path
+}
+
+func copyFixture(t *testing.T, fixture string, path string) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", fixture))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+}
+
+func TestGeoIPCountry(t *testing.T) {
+	geoIP, _ := loadGeoIP(t, "geoip-country.mmdb")
+
+	tests := []struct {
+		name string
+		ip   netip.Addr
+		want string
+	}{
+		{"country", netip.MustParseAddr("81.2.3.4"), "DE"},
+		{"ipv4 mapped ipv6", netip.MustParseAddr("::ffff:81.2.3.4"), "DE"},
+		{"narrower network", netip.MustParseAddr("9.9.200.1"), "US"},
+		{"registered country fallback", netip.MustParseAddr("1.1.1.1"), "AU"},
+		{"unknown address", netip.MustParseAddr("8.8.8.8"), ""},
+		{"ipv6 in ipv4 database", netip.MustParseAddr("2001:db8::1"), ""},
+		{"invalid address", netip.Addr{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := geoIP.Country(tt.ip); got != tt.want {
+				t.Errorf("Country(%v) = %q, want %q", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeoIPCountryNotLoaded(t *testing.T) {
+	geoIP := NewGeoIP(filepath.Join(t.TempDir(), "missing.mmdb"), time.Minute)
+
+	if err := geoIP.Reload(); err == nil {
+		t.Error("Reload() of missing file error = nil")
+	}
+	if got := geoIP.Country(netip.MustParseAddr("81.2.3.4")); got != "" {
+		t.Errorf("Country() without database = %q, want empty", got)
+	}
+}
+
+func TestGeoIPReload(t *testing.T) {
+	geoIP, path := loadGeoIP(t, "geoip-country.mmdb")
+	ip := netip.MustParseAddr("81.2.3.4")
+
+	if geoIP.changed() {
+		t.Fatal("changed() = true for the loaded file")
+	}
+
+	copyFixture(t, "geoip-country-updated.mmdb", path)
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if !geoIP.changed() {
+		t.Fatal("changed() = false after file was replaced")
+	}
+
+	geoIP.reload()
+	if got := geoIP.Country(ip); got != "AT" {
+		t.Errorf("Country() after reload = %q, want AT", got)
+	}
+	if got := geoIP.Country(netip.MustParseAddr("9.9.200.1")); got != "" {
+		t.Errorf("Country() of network missing from new database = %q, want empty", got)
+	}
+
+	if err := os.WriteFile(path, []byte("not a database"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := geoIP.Reload(); err == nil {
+		t.Error("Reload() of broken file error = nil")
+	}
+	if got := geoIP.Country(ip); got != "AT" {
+		t.Errorf("Country() after failed reload = %q, want previous database answer AT", got)
+	}
+}
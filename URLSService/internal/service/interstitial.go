@@ -0,0 +1,24 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+)
+
+// ErrConfirmationRequired means link shows a warning page before redirecting
+var ErrConfirmationRequired = errors.New("confirmation required")
+
+// ConfirmationRequiredError carries link to show on the warning page, already targeted for
+// the visitor so the page names where continuing leads. It matches ErrConfirmationRequired.
+type ConfirmationRequiredError struct {
+	URL *domain.URL
+}
+
+func (e *ConfirmationRequiredError) Error() string {
+	return ErrConfirmationRequired.Error()
+}
+
+func (e *ConfirmationRequiredError) Unwrap() error {
+	return ErrConfirmationRequired
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+)
+
+const (
+	maxTagLength        = 32
+	maxCollectionLength = 64
+	maxTagsPerURL       = 20
+)
+
+var (
+	ErrInvalidLabel = errors.New("invalid label")
+	ErrLabelExists  = errors.New("label already exists")
+)
+
+// labelPattern allows letters and digits of any script, spaces and '.', '-', '_' inside
+var labelPattern = regexp.MustCompile(`^[\p{L}\p{N}]([\p{L}\p{N} ._-]*[\p{L}\p{N}])?$`)
+
+var maxLabelLengths = map[domain.LabelKind]int{
+	domain.LabelTag:        maxTagLength,
+	domain.LabelCollection: maxCollectionLength,
+}
+
+type LabelService interface {
+	// SetURLTags replaces tags of owned link and returns them normalized
+	SetURLTags(ctx context.Context, shortCode string, userID string, tags []string) ([]string, error)
+	// SetURLCollection moves owned link to collection, empty name removes it from any
+	SetURLCollection(ctx context.Context, shortCode string, userID string, collection string) (string, error)
+	ListLabels(ctx context.Context, kind domain.LabelKind, userID string) ([]domain.Label, error)
+	RenameLabel(ctx context.Context, kind domain.LabelKind, userID string, name string, newName string) (string, error)
+	DeleteLabel(ctx context.Context, kind domain.LabelKind, userID string, name string) error
+}
+
+type labelService struct {
+	repo repository.LabelRepository
+}
+
+func NewLabelService(repo repository.LabelRepository) LabelService {
+	return &labelService{repo: repo}
+}
+
+func (s *labelService) SetURLTags(ctx context.Context, shortCode string, userID string, tags []string) ([]string, error) {
+	if shortCode == "" {
+		return nil, ErrInvalidShortCode
+	}
+	if userID == "" {
+		return nil, ErrForbidden
+	}
+
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetURLTags(ctx, shortCode, userID, tags); err != nil {
+		return nil, mapLabelError(err)
+	}
+
+	return tags, nil
+}
+
+func (s *labelService) SetURLCollection(ctx context.Context, shortCode string, userID string, collection string) (string, error) {
+	if shortCode == "" {
+		return "", ErrInvalidShortCode
+	}
+	if userID == "" {
+		return "", ErrForbidden
+	}
+
+	collection, err := normalizeCollection(collection)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.repo.SetURLCollection(ctx, shortCode, userID, collection); err != nil {
+		return "", mapLabelError(err)
+	}
+
+	return collection, nil
+}
+
+func (s *labelService) ListLabels(ctx context.Context, kind domain.LabelKind, userID string) ([]domain.Label, error) {
+	if userID == "" {
+		return nil, ErrForbidden
+	}
+
+	return s.repo.ListLabels(ctx, kind, userID)
+}
+
+func (s *labelService) RenameLabel(ctx context.Context, kind domain.LabelKind, userID string, name string, newName string) (string, error) {
+	if userID == "" {
+		return "", ErrForbidden
+	}
+
+	newName, err := normalizeLabel(kind, newName)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.repo.RenameLabel(ctx, kind, userID, name, newName); err != nil {
+		return "", mapLabelError(err)
+	}
+
+	return newName, nil
+}
+
+func (s *labelService) DeleteLabel(ctx context.Context, kind domain.LabelKind, userID string, name string) error {
+	if userID == "" {
+		return ErrForbidden
+	}
+
+	if err := s.repo.DeleteLabel(ctx, kind, userID, name); err != nil {
+		return mapLabelError(err)
+	}
+
+	return nil
+}
+
+// normalizeTags trims tags and drops case-insensitive duplicates keeping the first spelling
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) > maxTagsPerURL {
+		return nil, fmt.Errorf("%w: at most %d tags per link", ErrInvalidLabel, maxTagsPerURL)
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag, err := normalizeLabel(domain.LabelTag, tag)
+		if err != nil {
+			return nil, err
+		}
+
+		key := strings.ToLower(tag)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized, nil
+}
+
+// normalizeCollection is normalizeLabel that keeps empty name meaning no collection
+func normalizeCollection(collection string) (string, error) {
+	if strings.TrimSpace(collection) == "" {
+		return "", nil
+	}
+
+	return normalizeLabel(domain.LabelCollection, collection)
+}
+
+// normalizeLabel trims name and collapses inner whitespace runs to single space
+func normalizeLabel(kind domain.LabelKind, name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+
+	maxLength := maxLabelLengths[kind]
+	if name == "" || utf8.RuneCountInString(name) > maxLength {
+		return "", fmt.Errorf("%w: %s length must be between 1 and %d characters", ErrInvalidLabel, kind, maxLength)
+	}
+	if !labelPattern.MatchString(name) {
+		return "", fmt.Errorf("%w: %s may contain only letters, digits, spaces, '.', '-' and '_'", ErrInvalidLabel, kind)
+	}
+
+	return name, nil
+}
+
+func mapLabelError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return ErrNotFound
+	case errors.Is(err, repository.ErrLabelExists):
+		return ErrLabelExists
+	default:
+		return err
+	}
+}
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxLinkCheckErrorLength fits url_health.last_error
+	maxLinkCheckErrorLength = 200
+	linkCheckSaveTimeout    = 5 * time.Second
+)
+
+// LinkHealthEvent is sent to owner when link's destination keeps failing
+type LinkHealthEvent struct {
+	URL      *domain.URL
+	Health   *domain.LinkHealth
+	Disabled bool // link was turned off because of failures
+}
+
+// LinkHealthNotifier tells link owners about failing destinations
+type LinkHealthNotifier interface {
+	Notify(ctx context.Context, event LinkHealthEvent) error
+}
+
+// LinkCheckerOptions configures LinkChecker
+type LinkCheckerOptions struct {
+	Interval        time.Duration // how often each destination is checked
+	PollInterval    time.Duration // how often links due for a check are looked up
+	BatchSize       int
+	Timeout         time.Duration
+	MaxRedirects    int
+	Concurrency     int // checks running at once
+	HostConcurrency int // checks of one host running at once
+	UserAgent       string
+	NotifyAfter     int // consecutive failures owner is notified at, 0 never
+	DisableAfter    int // consecutive failures link is turned off at, 0 never
+	// AllowPrivateHosts lets checker reach private networks, for development and tests only
+	AllowPrivateHosts bool
+}
+
+// LinkChecker periodically requests destinations of active links and records whether they still respond.
+// Not found, gone, server errors and unreachable hosts count as failures; other statuses,
+// e.g. 401 or 429, mean destination is alive even if it doesn't let the checker in.
+type LinkChecker struct {
+	opts     LinkCheckerOptions
+	client   *http.Client
+	health   repository.HealthRepository
+	urls     repository.URLRepository
+	lock     repository.JobLock
+	notifier LinkHealthNotifier
+}
+
+// NewLinkChecker creates checker, urls should evict cache on update since failing links
+// may be disabled, notifier may be nil
+func NewLinkChecker(
+	health repository.HealthRepository,
+	urls repository.URLRepository,
+	lock repository.JobLock,
+	notifier LinkHealthNotifier,
+	opts LinkCheckerOptions,
+) *LinkChecker {
+	return &LinkChecker{
+		opts:     opts,
+		client:   newOutboundClient(opts.Timeout, opts.MaxRedirects, opts.AllowPrivateHosts),
+		health:   health,
+		urls:     urls,
+		lock:     lock,
+		notifier: notifier,
+	}
+}
+
+// Start checks due links on every tick until ctx is cancelled
+func (c *LinkChecker) Start(ctx context.Context) {
+	ticker := time.NewTicker(c.opts.PollInterval)
+	defer ticker.Stop()
+
+	logger.AppLogInfo("Link checker started",
+		zap.Duration("interval", c.opts.Interval),
+		zap.Duration("poll_interval", c.opts.PollInterval),
+		zap.Int("batch_size", c.opts.BatchSize),
+		zap.Int("disable_after", c.opts.DisableAfter),
+	)
+
+	// Run immediately on start
+	c.checkDue(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			c.checkDue(ctx)
+		case <-ctx.Done():
+			logger.AppLogInfo("Link checker stopped")
+			return
+		}
+	}
+}
+
+func (c *LinkChecker) checkDue(ctx context.Context) {
+	started := time.Now()
+	var checked, failing, disabled atomic.Int64
+
+	ran, err := c.lock.TryRun(ctx, func(ctx context.Context) error {
+		urls, err := c.health.ListDue(ctx, started.Add(-c.opts.Interval), c.opts.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		hosts := newHostLimiter(c.opts.HostConcurrency)
+		running := make(chan struct{}, c.opts.Concurrency)
+		var wg sync.WaitGroup
+
+		for _, u := range urls {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				// Host slot first, so checks waiting on a busy host don't hold global slots
+				release, ok := hosts.acquire(ctx, destinationHost(u.OriginalURL))
+				if !ok {
+					return
+				}
+				defer release()
+
+				select {
+				case running <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				defer func() { <-running }()
+
+				health, wasDisabled, ok := c.checkURL(ctx, u)
+				if !ok {
+					return
+				}
+				checked.Add(1)
+				if !health.Healthy() {
+					failing.Add(1)
+				}
+				if wasDisabled {
+					disabled.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		return nil
+	})
+
+	fields := []zap.Field{
+		zap.Int64("checked", checked.Load()),
+		zap.Int64("failing", failing.Load()),
+		zap.Int64("disabled", disabled.Load()),
+		zap.Duration("took", time.Since(started)),
+	}
+	switch {
+	case err != nil:
+		logger.AppLogError("Failed to check links", append(fields, zap.Error(err))...)
+	case !ran:
+		logger.AppLogDebug("Link check skipped, another replica holds the lock")
+	case checked.Load() > 0:
+		logger.AppLogInfo("Links checked", fields...)
+	default:
+		logger.AppLogDebug("No links due for check")
+	}
+}
+
+// checkURL checks and records one destination, ok is false when check was interrupted or not saved
+func (c *LinkChecker) checkURL(ctx context.Context, u *domain.URL) (health *domain.LinkHealth, disabled bool, ok bool) {
+	status, err := c.probe(ctx, u.OriginalURL)
+	if ctx.Err() != nil {
+		return nil, false, false
+	}
+
+	health = &domain.LinkHealth{
+		SourceURL:  u.OriginalURL,
+		StatusCode: status,
+		CheckedAt:  time.Now(),
+	}
+	if err != nil {
+		health.Error = cleanMetadataText(err.Error(), maxLinkCheckErrorLength)
+	}
+
+	// Results are saved even during shutdown, the request is already done
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), linkCheckSaveTimeout)
+	defer cancel()
+
+	health.ConsecutiveFailures, err = c.health.Record(saveCtx, u.ShortCode, health, isDeadLink(status, err))
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			logger.AppLogError("Failed to record link check",
+				zap.String("short_code", u.ShortCode),
+				zap.Error(err),
+			)
+		}
+		return nil, false, false
+	}
+
+	failures := health.ConsecutiveFailures
+	switch {
+	case failures == 0:
+	case c.opts.DisableAfter > 0 && failures >= c.opts.DisableAfter:
+		disabled = c.disable(saveCtx, u)
+		if disabled {
+			c.notify(saveCtx, LinkHealthEvent{URL: u, Health: health, Disabled: true})
+		}
+	case c.opts.NotifyAfter > 0 && failures == c.opts.NotifyAfter:
+		c.notify(saveCtx, LinkHealthEvent{URL: u, Health: health})
+	}
+
+	return health, disabled, true
+}
+
+// probe returns final status of destination after redirects, 0 when it didn't respond.
+// HEAD is tried first, servers that don't support it or answer it wrongly get a GET.
+func (c *LinkChecker) probe(ctx context.Context, rawURL string) (int, error) {
+	status, err := c.request(ctx, http.MethodHead, rawURL)
+	if err == nil && status >= http.StatusBadRequest {
+		status, err = c.request(ctx, http.MethodGet, rawURL)
+	}
+
+	return status, err
+}
+
+func (c *LinkChecker) request(ctx context.Context, method string, rawURL string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", c.opts.UserAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	// Body isn't needed, connection is dropped instead of draining it
+	resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
+
+// disable turns failing link off, it is left alone if owner changed it since it was listed
+func (c *LinkChecker) disable(ctx context.Context, u *domain.URL) bool {
+	disabled := *u
+	disabled.Disabled = true
+
+	if _, err := c.urls.Update(ctx, &disabled, u.Version); err != nil {
+		if !errors.Is(err, repository.ErrVersionMismatch) {
+			logger.AppLogError("Failed to disable failing link",
+				zap.String("short_code", u.ShortCode),
+				zap.Error(err),
+			)
+		}
+		return false
+	}
+
+	logger.AppLogInfo("Failing link disabled", zap.String("short_code", u.ShortCode))
+	return true
+}
+
+func (c *LinkChecker) notify(ctx context.Context, event LinkHealthEvent) {
+	// Anonymous links have nobody to tell
+	if c.notifier == nil || event.URL.UserID == nil {
+		return
+	}
+
+	if err := c.notifier.Notify(ctx, event); err != nil {
+		logger.AppLogError("Failed to notify link owner",
+			zap.String("short_code", event.URL.ShortCode),
+			zap.Error(err),
+		)
+	}
+}
+
+// isDeadLink tells failures from responses of a live destination
+func isDeadLink(status int, err error) bool {
+	return err != nil ||
+		status == http.StatusNotFound ||
+		status == http.StatusGone ||
+		status >= http.StatusInternalServerError
+}
+
+// destinationHost returns lowercased host of destination, it is what checks are limited by
+func destinationHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// hostLimiter bounds concurrent checks per host
+type hostLimiter struct {
+	mu    sync.Mutex
+	limit int
+	slots map[string]chan struct{}
+}
+
+func newHostLimiter(limit int) *hostLimiter {
+	return &hostLimiter{
+		limit: limit,
+		slots: make(map[string]chan struct{}),
+	}
+}
+
+// acquire waits for free slot of host, ok is false when ctx was cancelled first
+func (l *hostLimiter) acquire(ctx context.Context, host string) (release func(), ok bool) {
+	l.mu.Lock()
+	slot, exists := l.slots[host]
+	if !exists {
+		slot = make(chan struct{}, l.limit)
+		l.slots[host] = slot
+	}
+	l.mu.Unlock()
+
+	select {
+	case slot <- struct{}{}:
+		return func() { <-slot }, true
+	case <-ctx.Done():
+		return nil, false
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+)
+
+type LinkHealthService interface {
+	// GetHealth returns latest destination check of URL owned by userID, nil when it wasn't checked yet
+	GetHealth(ctx context.Context, shortCode string, userID string) (*domain.LinkHealth, error)
+}
+
+type linkHealthService struct {
+	urls   repository.URLRepository
+	health repository.HealthRepository
+}
+
+func NewLinkHealthService(urls repository.URLRepository, health repository.HealthRepository) LinkHealthService {
+	return &linkHealthService{
+		urls:   urls,
+		health: health,
+	}
+}
+
+func (s *linkHealthService) GetHealth(ctx context.Context, shortCode string, userID string) (*domain.LinkHealth, error) {
+	if shortCode == "" {
+		return nil, ErrInvalidShortCode
+	}
+	if userID == "" {
+		return nil, ErrForbidden
+	}
+
+	if _, err := s.urls.GetByShortCodeAndUserID(ctx, shortCode, userID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, ErrNotFound
+		case errors.Is(err, repository.ErrForbidden):
+			return nil, ErrForbidden
+		default:
+			return nil, err
+		}
+	}
+
+	health, err := s.health.Get(ctx, shortCode)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+
+	return health, err
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Events sent to link owners
+const (
+	LinkEventFailing  = "link.failing"
+	LinkEventDisabled = "link.disabled"
+)
+
+// linkHealthPayload is JSON body of webhook request
+type linkHealthPayload struct {
+	Event               string  `json:"event"`
+	ShortCode           string  `json:"short_code"`
+	UserID              *string `json:"user_id"`
+	OriginalURL         string  `json:"original_url"`
+	StatusCode          int     `json:"status_code,omitempty"`
+	Error               string  `json:"error,omitempty"`
+	ConsecutiveFailures int     `json:"consecutive_failures"`
+	CheckedAt           string  `json:"checked_at"`
+}
+
+// WebhookHealthNotifier posts link health events as JSON to a fixed operator configured URL,
+// e.g. a mailer or chat bot that knows how to reach the owner. Body is signed with
+// HMAC-SHA256 in X-Signature header when secret is set.
+type WebhookHealthNotifier struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewWebhookHealthNotifier(url string, secret string, timeout time.Duration) *WebhookHealthNotifier {
+	return &WebhookHealthNotifier{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (n *WebhookHealthNotifier) Notify(ctx context.Context, event LinkHealthEvent) error {
+	payload := linkHealthPayload{
+		Event:               LinkEventFailing,
+		ShortCode:           event.URL.ShortCode,
+		UserID:              event.URL.UserID,
+		OriginalURL:         event.URL.OriginalURL,
+		StatusCode:          event.Health.StatusCode,
+		Error:               event.Health.Error,
+		ConsecutiveFailures: event.Health.ConsecutiveFailures,
+		CheckedAt:           event.Health.CheckedAt.Format(time.RFC3339),
+	}
+	if event.Disabled {
+		payload.Event = LinkEventDisabled
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.secret))
+		mac.Write(body)
+		req.Header.Set("X-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
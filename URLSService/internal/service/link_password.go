@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/ratelimit"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	minPasswordLength = 4
+	// maxPasswordLength is bcrypt input limit, longer passwords would be silently truncated
+	maxPasswordLength = 72
+)
+
+var (
+	ErrInvalidPassword  = errors.New("invalid password")
+	ErrPasswordRequired = errors.New("password required")
+	ErrWrongPassword    = errors.New("wrong password")
+	ErrTooManyAttempts  = errors.New("too many password attempts")
+)
+
+// TooManyAttemptsError is returned while link is locked out, it matches ErrTooManyAttempts
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (e *TooManyAttemptsError) Error() string {
+	return fmt.Sprintf("%v, retry after %v", ErrTooManyAttempts, e.RetryAfter.Round(time.Second))
+}
+
+func (e *TooManyAttemptsError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+// LinkPasswords hashes link passwords and checks them with per-code attempt limiting
+type LinkPasswords struct {
+	limiter ratelimit.AttemptLimiter
+	cost    int
+}
+
+func NewLinkPasswords(limiter ratelimit.AttemptLimiter, cost int) *LinkPasswords {
+	return &LinkPasswords{
+		limiter: limiter,
+		cost:    cost,
+	}
+}
+
+// Hash validates password and returns its bcrypt hash
+func (p *LinkPasswords) Hash(password string) (string, error) {
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return "", fmt.Errorf("%w: must be %d to %d bytes long", ErrInvalidPassword, minPasswordLength, maxPasswordLength)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), p.cost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return string(hash), nil
+}
+
+// Verify lets visitor through public links or when password matches the stored hash.
+// Every check spends an attempt up front, a correct password gives the attempts back.
+func (p *LinkPasswords) Verify(ctx context.Context, url *domain.URL, password string) error {
+	if url.PasswordHash == "" {
+		return nil
+	}
+	if password == "" {
+		return ErrPasswordRequired
+	}
+
+	retryAfter, err := p.limiter.Reserve(ctx, url.ShortCode)
+	if err != nil {
+		return err
+	}
+	if retryAfter > 0 {
+		logger.SecurityLogWarnCtx(ctx, "Password attempt on locked link",
+			zap.String("short_code", url.ShortCode),
+			zap.Duration("retry_after", retryAfter),
+		)
+		return &TooManyAttemptsError{RetryAfter: retryAfter}
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(url.PasswordHash), []byte(password))
+	if err == nil {
+		if err := p.limiter.Reset(ctx, url.ShortCode); err != nil {
+			logger.SecurityLogErrorCtx(ctx, "Failed to reset password attempts",
+				zap.Error(err),
+				zap.String("short_code", url.ShortCode),
+			)
+		}
+		return nil
+	}
+	if !errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return fmt.Errorf("failed to check password: %w", err)
+	}
+
+	logger.SecurityLogWarnCtx(ctx, "Wrong link password", zap.String("short_code", url.ShortCode))
+
+	return ErrWrongPassword
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	// metadataRetryAfter is how long a failed fetch is kept before the page is tried again
+	metadataRetryAfter = 24 * time.Hour
+	// maxMetadataErrorLength fits url_metadata.fetch_error
+	maxMetadataErrorLength = 200
+	metadataSaveTimeout    = 5 * time.Second
+)
+
+// MetadataQueue schedules fetching of link destination details
+type MetadataQueue interface {
+	Enqueue(ctx context.Context, url *domain.URL)
+}
+
+// metadataJob is a destination to fetch for link
+type metadataJob struct {
+	shortCode   string
+	originalURL string
+}
+
+// MetadataCollector fetches destination pages of new and edited links in background.
+// Links are queued in memory right after they are saved, a periodic sweep picks up
+// the ones dropped on full queue or lost on restart.
+type MetadataCollector struct {
+	fetcher        *MetadataFetcher
+	repo           repository.MetadataRepository
+	lock           repository.JobLock
+	jobs           chan metadataJob
+	workers        int
+	sweepInterval  time.Duration
+	sweepBatchSize int
+}
+
+func NewMetadataCollector(
+	fetcher *MetadataFetcher,
+	repo repository.MetadataRepository,
+	lock repository.JobLock,
+	workers int,
+	queueSize int,
+	sweepInterval time.Duration,
+	sweepBatchSize int,
+) *MetadataCollector {
+	return &MetadataCollector{
+		fetcher:        fetcher,
+		repo:           repo,
+		lock:           lock,
+		jobs:           make(chan metadataJob, queueSize),
+		workers:        workers,
+		sweepInterval:  sweepInterval,
+		sweepBatchSize: sweepBatchSize,
+	}
+}
+
+// Enqueue queues link without blocking, when queue is full the sweep gets to it later
+func (c *MetadataCollector) Enqueue(ctx context.Context, url *domain.URL) {
+	select {
+	case c.jobs <- metadataJob{shortCode: url.ShortCode, originalURL: url.OriginalURL}:
+	default:
+		logger.AppLogWarnCtx(ctx, "Metadata queue is full, leaving link to sweep",
+			zap.String("short_code", url.ShortCode),
+		)
+	}
+}
+
+// Start runs workers and sweeps until ctx is cancelled, queued links are left to next start's sweep
+func (c *MetadataCollector) Start(ctx context.Context) {
+	logger.AppLogInfo("Metadata collector started",
+		zap.Int("workers", c.workers),
+		zap.Duration("sweep_interval", c.sweepInterval),
+		zap.Int("sweep_batch_size", c.sweepBatchSize),
+	)
+
+	done := make(chan struct{})
+	for range c.workers {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			c.work(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(c.sweepInterval)
+	defer ticker.Stop()
+
+	// Run immediately on start
+	c.sweep(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			c.sweep(ctx)
+		case <-ctx.Done():
+			for range c.workers {
+				<-done
+			}
+			logger.AppLogInfo("Metadata collector stopped")
+			return
+		}
+	}
+}
+
+func (c *MetadataCollector) work(ctx context.Context) {
+	for {
+		select {
+		case job := <-c.jobs:
+			c.collect(ctx, job)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// collect fetches and stores metadata of one link, failures are stored too so the sweep skips them for a while
+func (c *MetadataCollector) collect(ctx context.Context, job metadataJob) {
+	meta, err := c.fetcher.Fetch(ctx, job.originalURL)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		logger.AppLogDebug("Failed to fetch destination metadata",
+			zap.String("short_code", job.shortCode),
+			zap.Error(err),
+		)
+		meta = &domain.LinkMetadata{
+			SourceURL:  job.originalURL,
+			FetchError: cleanMetadataText(err.Error(), maxMetadataErrorLength),
+			FetchedAt:  time.Now(),
+		}
+	}
+
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), metadataSaveTimeout)
+	defer cancel()
+
+	if err := c.repo.Save(saveCtx, job.shortCode, meta); err != nil {
+		logger.AppLogError("Failed to save destination metadata",
+			zap.String("short_code", job.shortCode),
+			zap.Error(err),
+		)
+	}
+}
+
+// sweep queues links missing metadata, one replica at a time
+func (c *MetadataCollector) sweep(ctx context.Context) {
+	queued := 0
+
+	ran, err := c.lock.TryRun(ctx, func(ctx context.Context) error {
+		urls, err := c.repo.ListMissing(ctx, time.Now().Add(-metadataRetryAfter), c.sweepBatchSize)
+		if err != nil {
+			return err
+		}
+
+		// Waits for free queue slots so the lock is held until workers picked the batch up
+		for _, url := range urls {
+			select {
+			case c.jobs <- metadataJob{shortCode: url.ShortCode, originalURL: url.OriginalURL}:
+				queued++
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		return nil
+	})
+
+	switch {
+	case err != nil:
+		logger.AppLogError("Failed to sweep links missing metadata", zap.Error(err))
+	case !ran:
+		logger.AppLogDebug("Metadata sweep skipped, another replica holds the lock")
+	case queued > 0:
+		logger.AppLogInfo("Links missing metadata queued", zap.Int("count", queued))
+	}
+}
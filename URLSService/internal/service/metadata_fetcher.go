@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	maxMetadataTitleLength       = 300
+	maxMetadataDescriptionLength = 1000
+	maxMetadataSiteNameLength    = 200
+	maxMetadataURLLength         = 2048
+)
+
+var (
+	ErrMetadataStatus  = errors.New("unexpected response status")
+	ErrMetadataNotHTML = errors.New("destination is not an HTML page")
+)
+
+// MetadataFetcherOptions configures MetadataFetcher
+type MetadataFetcherOptions struct {
+	Timeout      time.Duration // whole request including redirects and body
+	MaxBodyBytes int64         // page is parsed up to this size, the rest is not downloaded
+	MaxRedirects int
+	UserAgent    string
+	// AllowPrivateHosts lets fetcher reach private networks, for development and tests only
+	AllowPrivateHosts bool
+}
+
+// MetadataFetcher downloads destination pages and reads their title, description, preview image and icon
+type MetadataFetcher struct {
+	opts   MetadataFetcherOptions
+	client *http.Client
+}
+
+func NewMetadataFetcher(opts MetadataFetcherOptions) *MetadataFetcher {
+	return &MetadataFetcher{
+		opts:   opts,
+		client: newOutboundClient(opts.Timeout, opts.MaxRedirects, opts.AllowPrivateHosts),
+	}
+}
+
+// Fetch downloads page at rawURL and returns its metadata, SourceURL is set to rawURL.
+// Relative links are resolved against the final URL after redirects.
+func (f *MetadataFetcher) Fetch(ctx context.Context, rawURL string) (*domain.LinkMetadata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", f.opts.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.1")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%w: %d", ErrMetadataStatus, resp.StatusCode)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if mediaType, _, err := mime.ParseMediaType(contentType); err != nil ||
+		(mediaType != "text/html" && mediaType != "application/xhtml+xml") {
+		return nil, ErrMetadataNotHTML
+	}
+
+	body, err := charset.NewReader(io.LimitReader(resp.Body, f.opts.MaxBodyBytes), contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := parsePageMetadata(body, resp.Request.URL)
+	meta.SourceURL = rawURL
+	meta.FetchedAt = time.Now()
+
+	return meta, nil
+}
+
+// parsePageMetadata reads document head, OpenGraph properties take precedence over plain tags.
+// Without an icon link the conventional /favicon.ico is assumed.
+func parsePageMetadata(r io.Reader, pageURL *url.URL) *domain.LinkMetadata {
+	var title, ogTitle, description, ogDescription, image, siteName, icon, touchIcon string
+	base := pageURL
+
+	tokenizer := html.NewTokenizer(r)
+	inTitle := false
+
+parse:
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			// EOF or body limit reached, whatever was read so far is used
+			break parse
+		case html.TextToken:
+			if inTitle && title == "" {
+				title = string(tokenizer.Text())
+			}
+		case html.EndTagToken:
+			if name, _ := tokenizer.TagName(); string(name) == "title" {
+				inTitle = false
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			attrs := map[string]string{}
+			for hasAttr {
+				var key, value []byte
+				key, value, hasAttr = tokenizer.TagAttr()
+				if _, seen := attrs[string(key)]; !seen {
+					attrs[string(key)] = string(value)
+				}
+			}
+
+			switch string(name) {
+			case "body":
+				break parse
+			case "title":
+				inTitle = true
+			case "base":
+				if href, err := base.Parse(attrs["href"]); err == nil && attrs["href"] != "" {
+					base = href
+				}
+			case "meta":
+				content := attrs["content"]
+				key := strings.ToLower(attrs["property"])
+				if key == "" {
+					key = strings.ToLower(attrs["name"])
+				}
+				switch key {
+				case "og:title":
+					ogTitle = firstNonEmpty(ogTitle, content)
+				case "og:description":
+					ogDescription = firstNonEmpty(ogDescription, content)
+				case "description":
+					description = firstNonEmpty(description, content)
+				case "og:image", "og:image:url", "og:image:secure_url":
+					image = firstNonEmpty(image, content)
+				case "og:site_name":
+					siteName = firstNonEmpty(siteName, content)
+				}
+			case "link":
+				for _, rel := range strings.Fields(strings.ToLower(attrs["rel"])) {
+					switch rel {
+					case "icon":
+						icon = firstNonEmpty(icon, attrs["href"])
+					case "apple-touch-icon":
+						touchIcon = firstNonEmpty(touchIcon, attrs["href"])
+					}
+				}
+			}
+		}
+	}
+
+	favicon := resolvePageURL(base, firstNonEmpty(icon, touchIcon))
+	if favicon == "" {
+		favicon = resolvePageURL(pageURL, "/favicon.ico")
+	}
+
+	return &domain.LinkMetadata{
+		Title:       cleanMetadataText(firstNonEmpty(ogTitle, title), maxMetadataTitleLength),
+		Description: cleanMetadataText(firstNonEmpty(ogDescription, description), maxMetadataDescriptionLength),
+		ImageURL:    resolvePageURL(base, image),
+		SiteName:    cleanMetadataText(siteName, maxMetadataSiteNameLength),
+		FaviconURL:  favicon,
+	}
+}
+
+// resolvePageURL makes ref absolute, references that aren't http(s) or are too long are dropped
+func resolvePageURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+
+	resolved, err := base.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if _, ok := defaultPorts[resolved.Scheme]; !ok || resolved.Host == "" {
+		return ""
+	}
+
+	result := resolved.String()
+	if len(result) > maxMetadataURLLength {
+		return ""
+	}
+
+	return result
+}
+
+// cleanMetadataText collapses whitespace, drops control characters and truncates to maxLength runes
+func cleanMetadataText(s string, maxLength int) string {
+	s = strings.ToValidUTF8(s, "")
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && !unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+	s = strings.Join(strings.Fields(s), " ")
+
+	if utf8.RuneCountInString(s) > maxLength {
+		s = strings.TrimSpace(string([]rune(s)[:maxLength]))
+	}
+
+	return s
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestMetadataFetcher(maxBodyBytes int64, allowPrivateHosts bool) *MetadataFetcher {
+	return NewMetadataFetcher(MetadataFetcherOptions{
+		Timeout:           5 * time.Second,
+		MaxBodyBytes:      maxBodyBytes,
+		MaxRedirects:      3,
+		UserAgent:         "URLSServiceBot/test",
+		AllowPrivateHosts: allowPrivateHosts,
+	})
+}
+
+// servePages serves HTML pages by path, other paths are not found
+func servePages(t *testing.T, pages map[string]string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(page))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestMetadataFetcherFetch(t *testing.T) {
+	server := servePages(t, map[string]string{
+		"/og": `<html><head>
+			<title>Plain title</title>
+			<meta name="description" content="Plain description">
+			<meta property="og:title" content="  OpenGraph
+				title ">
+			<meta property="og:description" content="OpenGraph description">
+			<meta property="og:site_name" content="Example Store">
+			<meta property="og:image" content="/images/preview.png">
+			<link rel="shortcut icon" href="static/icon.png">
+			</head><body><meta property="og:title" content="From body"></body></html>`,
+		"/articles/base": `<html><head>
+			<base href="/assets/">
+			<title>Base page</title>
+			<meta property="og:image" content="img/cover.jpg">
+			<link rel="apple-touch-icon" href="touch.png">
+			</head></html>`,
+		"/articles/plain": `<html><head><title>No icon</title>
+			<meta name="description" content="Only plain tags"></head></html>`,
+	})
+
+	tests := []struct {
+		name            string
+		path            string
+		wantTitle       string
+		wantDescription string
+		wantSiteName    string
+		wantImage       string
+		wantFavicon     string
+	}{
+		{
+			name:            "opengraph takes precedence over plain tags",
+			path:            "/og",
+			wantTitle:       "OpenGraph title",
+			wantDescription: "OpenGraph description",
+			wantSiteName:    "Example Store",
+			wantImage:       server.URL + "/images/preview.png",
+			wantFavicon:     server.URL + "/static/icon.png",
+		},
+		{
+			name:        "image and icon resolved against base",
+			path:        "/articles/base",
+			wantTitle:   "Base page",
+			wantImage:   server.URL + "/assets/img/cover.jpg",
+			wantFavicon: server.URL + "/assets/touch.png",
+		},
+		{
+			name:            "favicon.ico assumed without icon link",
+			path:            "/articles/plain",
+			wantTitle:       "No icon",
+			wantDescription: "Only plain tags",
+			wantFavicon:     server.URL + "/favicon.ico",
+		},
+	}
+
+	fetcher := newTestMetadataFetcher(64<<10, true)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta, err := fetcher.Fetch(context.Background(), server.URL+tt.path)
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+
+			if meta.SourceURL != server.URL+tt.path {
+				t.Errorf("SourceURL = %q, want %q", meta.SourceURL, server.URL+tt.path)
+			}
+			if meta.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", meta.Title, tt.wantTitle)
+			}
+			if meta.Description != tt.wantDescription {
+				t.Errorf("Description = %q, want %q", meta.Description, tt.wantDescription)
+			}
+			if meta.SiteName != tt.wantSiteName {
+				t.Errorf("SiteName = %q, want %q", meta.SiteName, tt.wantSiteName)
+			}
+			if meta.ImageURL != tt.wantImage {
+				t.Errorf("ImageURL = %q, want %q", meta.ImageURL, tt.wantImage)
+			}
+			if meta.FaviconURL != tt.wantFavicon {
+				t.Errorf("FaviconURL = %q, want %q", meta.FaviconURL, tt.wantFavicon)
+			}
+		})
+	}
+}
+
+func TestMetadataFetcherFetchErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/image":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("\x89PNG"))
+		case "/error":
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("<title>Maintenance</title>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr error
+	}{
+		{"not html", "/image", ErrMetadataNotHTML},
+		{"server error", "/error", ErrMetadataStatus},
+		{"not found", "/missing", ErrMetadataStatus},
+	}
+
+	fetcher := newTestMetadataFetcher(64<<10, true)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := fetcher.Fetch(context.Background(), server.URL+tt.path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Fetch() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMetadataFetcherFetchBodyLimit(t *testing.T) {
+	// Tags past the limit are never downloaded, so the late OpenGraph title can't win
+	server := servePages(t, map[string]string{
+		"/long": "<html><head><title>Early title</title><!--" + strings.Repeat("x", 4096) + "-->" +
+			`<meta property="og:title" content="Late title"></head></html>`,
+	})
+
+	meta, err := newTestMetadataFetcher(1024, true).Fetch(context.Background(), server.URL+"/long")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if meta.Title != "Early title" {
+		t.Errorf("Title = %q, want %q", meta.Title, "Early title")
+	}
+
+	meta, err = newTestMetadataFetcher(64<<10, true).Fetch(context.Background(), server.URL+"/long")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if meta.Title != "Late title" {
+		t.Errorf("Title without limit = %q, want %q", meta.Title, "Late title")
+	}
+}
+
+func TestMetadataFetcherFetchPrivateHost(t *testing.T) {
+	var requested atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested.Store(true)
+	}))
+	defer server.Close()
+
+	_, err := newTestMetadataFetcher(64<<10, false).Fetch(context.Background(), server.URL)
+	if !errors.Is(err, ErrDestinationPrivateHost) {
+		t.Errorf("Fetch() error = %v, want %v", err, ErrDestinationPrivateHost)
+	}
+	if requested.Load() {
+		t.Error("request reached server on loopback address")
+	}
+}
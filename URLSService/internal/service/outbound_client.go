@@ -0,0 +1,65 @@
+package service
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrDestinationPrivateHost = errors.New("destination resolves to a private address")
+	ErrTooManyRedirects       = errors.New("too many redirects")
+	ErrRedirectScheme         = errors.New("redirect to unsupported scheme")
+)
+
+// newOutboundClient creates client for requests to user supplied destinations.
+// Every connection, redirects included, is checked after DNS resolution, so a public host name
+// can't be used to reach internal services.
+func newOutboundClient(timeout time.Duration, maxRedirects int, allowPrivateHosts bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+	}
+	if !allowPrivateHosts {
+		dialer.Control = rejectPrivateAddress
+	}
+
+	transport := &http.Transport{
+		// Proxy would dial on client's behalf and bypass the address check
+		Proxy:                  nil,
+		DialContext:            dialer.DialContext,
+		TLSHandshakeTimeout:    timeout,
+		ResponseHeaderTimeout:  timeout,
+		MaxResponseHeaderBytes: 64 << 10,
+		MaxIdleConns:           10,
+		IdleConnTimeout:        30 * time.Second,
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxRedirects {
+				return ErrTooManyRedirects
+			}
+			if _, ok := defaultPorts[req.URL.Scheme]; !ok {
+				return ErrRedirectScheme
+			}
+			return nil
+		},
+	}
+}
+
+// rejectPrivateAddress is net.Dialer control hook refusing non-public addresses
+func rejectPrivateAddress(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !IsPublicIP(addrPort.Addr()) {
+		return ErrDestinationPrivateHost
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// QR code output formats
+const (
+	QRFormatPNG = "png"
+	QRFormatSVG = "svg"
+)
+
+const maxQRMargin = 16
+
+var ErrInvalidQROptions = errors.New("invalid QR code options")
+
+// qrLevels maps error correction level names to recoverable share of damaged code
+var qrLevels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,     // 7%
+	"M": qrcode.Medium,  // 15%
+	"Q": qrcode.High,    // 25%
+	"H": qrcode.Highest, // 30%
+}
+
+// QROptions describes rendered QR code, Size is image side in pixels and Margin is
+// quiet zone width in modules
+type QROptions struct {
+	Format     string
+	Size       int
+	Margin     int
+	Level      string
+	Foreground color.NRGBA
+	Background color.NRGBA
+}
+
+// RenderQRCode encodes content as QR code image and returns its bytes
+func RenderQRCode(content string, opts QROptions) ([]byte, error) {
+	level, ok := qrLevels[opts.Level]
+	if !ok {
+		return nil, fmt.Errorf("%w: level must be one of L, M, Q, H", ErrInvalidQROptions)
+	}
+	if opts.Margin < 0 || opts.Margin > maxQRMargin {
+		return nil, fmt.Errorf("%w: margin must be between 0 and %d", ErrInvalidQROptions, maxQRMargin)
+	}
+
+	code, err := qrcode.New(content, level)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	// Quiet zone is drawn here, its width is configurable
+	code.DisableBorder = true
+	modules := code.Bitmap()
+
+	side := len(modules) + 2*opts.Margin
+	if opts.Size < side {
+		return nil, fmt.Errorf("%w: size must be at least %d pixels for this link", ErrInvalidQROptions, side)
+	}
+
+	switch opts.Format {
+	case QRFormatPNG:
+		return renderQRPNG(modules, side, opts)
+	case QRFormatSVG:
+		return renderQRSVG(modules, side, opts), nil
+	default:
+		return nil, fmt.Errorf("%w: format must be png or svg", ErrInvalidQROptions)
+	}
+}
+
+// renderQRPNG scales modules by whole pixels and centers them, leftover pixels extend the margin
+func renderQRPNG(modules [][]bool, side int, opts QROptions) ([]byte, error) {
+	scale := opts.Size / side
+	offset := (opts.Size-scale*side)/2 + opts.Margin*scale
+
+	img := image.NewPaletted(image.Rect(0, 0, opts.Size, opts.Size), color.Palette{opts.Background, opts.Foreground})
+	for y, row := range modules {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				line := img.Pix[(offset+y*scale+dy)*img.Stride:]
+				for dx := 0; dx < scale; dx++ {
+					line[offset+x*scale+dx] = 1
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// renderQRSVG draws dark modules as one path in module units, viewer scales it to Size
+func renderQRSVG(modules [][]bool, side int, opts QROptions) []byte {
+	var path strings.Builder
+	for y, row := range modules {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x+opts.Margin, y+opts.Margin)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		opts.Size, opts.Size, side, side)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" %s/>`, side, side, svgFill(opts.Background))
+	fmt.Fprintf(&buf, `<path d="%s" %s/>`, path.String(), svgFill(opts.Foreground))
+	buf.WriteString(`</svg>`)
+
+	return buf.Bytes()
+}
+
+func svgFill(c color.NRGBA) string {
+	fill := fmt.Sprintf(`fill="#%02x%02x%02x"`, c.R, c.G, c.B)
+	if c.A < 0xff {
+		fill += ` fill-opacity="` + strconv.FormatFloat(float64(c.A)/0xff, 'f', 3, 64) + `"`
+	}
+	return fill
+}
+
+// ParseQRColor reads RRGGBB or RRGGBBAA hex color, leading '#' is optional
+func ParseQRColor(s string) (color.NRGBA, error) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 && len(s) != 8 {
+		return color.NRGBA{}, fmt.Errorf("%w: color must be RRGGBB or RRGGBBAA hex, got %q", ErrInvalidQROptions, s)
+	}
+
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("%w: color must be RRGGBB or RRGGBBAA hex, got %q", ErrInvalidQROptions, s)
+	}
+	if len(s) == 6 {
+		v = v<<8 | 0xff
+	}
+
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+)
+
+const (
+	// MaxTargetRules limits targeting rules of one link
+	MaxTargetRules = 10
+	// MaxTargetCountries limits countries of one rule
+	MaxTargetCountries = 50
+)
+
+var ErrInvalidTargeting = errors.New("invalid targeting rules")
+
+// CountryLookup finds country of IP address, empty when unknown
+type CountryLookup interface {
+	Country(ip netip.Addr) string
+}
+
+var validClientOS = map[domain.ClientOS]bool{
+	domain.ClientOSIOS:      true,
+	domain.ClientOSAndroid:  true,
+	domain.ClientOSWindows:  true,
+	domain.ClientOSMacOS:    true,
+	domain.ClientOSLinux:    true,
+	domain.ClientOSChromeOS: true,
+}
+
+var validDeviceClasses = map[domain.DeviceClass]bool{
+	domain.DeviceMobile:  true,
+	domain.DeviceTablet:  true,
+	domain.DeviceDesktop: true,
+}
+
+// prepareTargets validates rules and normalizes their destinations the same way as link's own
+func (s *urlService) prepareTargets(ctx context.Context, rules []domain.TargetRule) ([]domain.TargetRule, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	if len(rules) > MaxTargetRules {
+		return nil, fmt.Errorf("%w: at most %d rules are allowed", ErrInvalidTargeting, MaxTargetRules)
+	}
+
+	prepared := make([]domain.TargetRule, 0, len(rules))
+	for i, rule := range rules {
+		if rule.OS == "" && rule.Device == "" && rule.Bot == nil && len(rule.Countries) == 0 {
+			return nil, fmt.Errorf("%w: rule %d has no conditions", ErrInvalidTargeting, i+1)
+		}
+		if rule.OS != "" && !validClientOS[rule.OS] {
+			return nil, fmt.Errorf("%w: rule %d has unknown os %q", ErrInvalidTargeting, i+1, rule.OS)
+		}
+		if rule.Device != "" && !validDeviceClasses[rule.Device] {
+			return nil, fmt.Errorf("%w: rule %d has unknown device %q", ErrInvalidTargeting, i+1, rule.Device)
+		}
+		countries, err := normalizeCountries(rule.Countries)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rule %d: %v", ErrInvalidTargeting, i+1, err)
+		}
+		rule.Countries = countries
+
+		normalized, err := s.validator.Normalize(rule.URL)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rule %d: %v", ErrInvalidTargeting, i+1, err)
+		}
+		if err := s.blocklist.Check(ctx, normalized); err != nil {
+			return nil, err
+		}
+
+		rule.URL = normalized
+		prepared = append(prepared, rule)
+	}
+
+	return prepared, nil
+}
+
+func (s *urlService) TargetURL(ctx context.Context, url *domain.URL, visitor *domain.Visitor) (*domain.URL, error) {
+	if len(url.Targets) == 0 {
+		return url, nil
+	}
+
+	destination, ok := selectTarget(url.Targets, visitorClient(visitor, url.Targets, s.countries))
+	if !ok {
+		return url, nil
+	}
+
+	// Rules may be added after link creation, so destination is checked on every resolve
+	if err := s.blocklist.Check(ctx, destination); err != nil {
+		return nil, err
+	}
+
+	targeted := *url
+	targeted.OriginalURL = destination
+	return &targeted, nil
+}
+
+// selectTarget returns destination of the first rule client matches, ok is false when link's own applies
+func selectTarget(rules []domain.TargetRule, client domain.ClientInfo) (destination string, ok bool) {
+	for _, rule := range rules {
+		if rule.Matches(client) {
+			return rule.URL, true
+		}
+	}
+
+	return "", false
+}
+
+// visitorClient describes visitor for rules, country is looked up only when some rule needs it
+// and country reported by trusted proxy wins over database, countries may be nil
+func visitorClient(visitor *domain.Visitor, rules []domain.TargetRule, countries CountryLookup) domain.ClientInfo {
+	client := ParseUserAgent(visitor.UserAgent)
+
+	needsCountry := slices.ContainsFunc(rules, func(rule domain.TargetRule) bool {
+		return len(rule.Countries) > 0
+	})
+	if !needsCountry {
+		return client
+	}
+
+	client.Country = normalizeCountry(visitor.Country)
+	if client.Country == "" && countries != nil {
+		client.Country = countries.Country(visitor.IP)
+	}
+
+	return client
+}
+
+// normalizeCountries uppercases and deduplicates ISO 3166-1 alpha-2 codes of a rule
+func normalizeCountries(codes []string) ([]string, error) {
+	if len(codes) == 0 {
+		return nil, nil
+	}
+	if len(codes) > MaxTargetCountries {
+		return nil, fmt.Errorf("at most %d countries are allowed", MaxTargetCountries)
+	}
+
+	normalized := make([]string, 0, len(codes))
+	for _, code := range codes {
+		country := normalizeCountry(code)
+		if country == "" {
+			return nil, fmt.Errorf("unknown country code %q", code)
+		}
+		if !slices.Contains(normalized, country) {
+			normalized = append(normalized, country)
+		}
+	}
+
+	return normalized, nil
+}
+
+// normalizeCountry returns uppercased two letter country code, empty for anything else.
+// Placeholders proxies send for unknown locations and Tor exits, XX and T1, are empty too.
+func normalizeCountry(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+		return ""
+	}
+	if code == "XX" {
+		return ""
+	}
+
+	return code
+}
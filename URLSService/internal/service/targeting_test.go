@@ -0,0 +1,156 @@
+package service
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+)
+
+const (
+	iPhoneUserAgent  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148"
+	androidUserAgent = "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 Chrome/120 Mobile Safari/537.36"
+	windowsUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 Chrome/120 Safari/537.36"
+)
+
+// countryLookupFunc adapts function to CountryLookup
+type countryLookupFunc func(ip netip.Addr) string
+
+func (f countryLookupFunc) Country(ip netip.Addr) string {
+	return f(ip)
+}
+
+func TestSelectTarget(t *testing.T) {
+	notBot := false
+	rules := []domain.TargetRule{
+		{OS: domain.ClientOSIOS, Countries: []string{"DE", "AT"}, URL: "https://example.com/ios-de"},
+		{OS: domain.ClientOSIOS, URL: "https://example.com/ios"},
+		{Countries: []string{"FR"}, URL: "https://example.com/fr"},
+		{Device: domain.DeviceMobile, Bot: &notBot, URL: "https://example.com/mobile"},
+	}
+
+	tests := []struct {
+		name     string
+		client   domain.ClientInfo
+		want     string
+		wantRule bool
+	}{
+		{
+			name:     "all conditions of first rule",
+			client:   domain.ClientInfo{OS: domain.ClientOSIOS, Device: domain.DeviceMobile, Country: "AT"},
+			want:     "https://example.com/ios-de",
+			wantRule: true,
+		},
+		{
+			name:     "earlier rule wins over later match",
+			client:   domain.ClientInfo{OS: domain.ClientOSIOS, Device: domain.DeviceMobile, Country: "FR"},
+			want:     "https://example.com/ios",
+			wantRule: true,
+		},
+		{
+			name:     "country only rule",
+			client:   domain.ClientInfo{OS: domain.ClientOSWindows, Device: domain.DeviceDesktop, Country: "FR"},
+			want:     "https://example.com/fr",
+			wantRule: true,
+		},
+		{
+			name:     "unknown country skips country rules",
+			client:   domain.ClientInfo{OS: domain.ClientOSAndroid, Device: domain.DeviceMobile},
+			want:     "https://example.com/mobile",
+			wantRule: true,
+		},
+		{
+			name:   "bot excluded",
+			client: domain.ClientInfo{OS: domain.ClientOSAndroid, Device: domain.DeviceMobile, Bot: true},
+		},
+		{
+			name:   "no rule matches falls back to link destination",
+			client: domain.ClientInfo{OS: domain.ClientOSWindows, Device: domain.DeviceDesktop, Country: "US"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := selectTarget(rules, tt.client)
+			if got != tt.want || ok != tt.wantRule {
+				t.Errorf("selectTarget() = %q, %v; want %q, %v", got, ok, tt.want, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestVisitorClient(t *testing.T) {
+	geoIP, _ := loadGeoIP(t, "geoip-country.mmdb")
+	countryRules := []domain.TargetRule{{Countries: []string{"DE"}, URL: "https://example.com/de"}}
+	deviceRules := []domain.TargetRule{{OS: domain.ClientOSIOS, URL: "https://example.com/ios"}}
+
+	tests := []struct {
+		name      string
+		visitor   domain.Visitor
+		rules     []domain.TargetRule
+		countries CountryLookup
+		want      domain.ClientInfo
+	}{
+		{
+			name:      "country from database",
+			visitor:   domain.Visitor{IP: netip.MustParseAddr("81.2.3.4"), UserAgent: iPhoneUserAgent},
+			rules:     countryRules,
+			countries: geoIP,
+			want:      domain.ClientInfo{OS: domain.ClientOSIOS, Device: domain.DeviceMobile, Country: "DE"},
+		},
+		{
+			name:      "trusted header wins over database",
+			visitor:   domain.Visitor{IP: netip.MustParseAddr("81.2.3.4"), UserAgent: androidUserAgent, Country: "fr"},
+			rules:     countryRules,
+			countries: geoIP,
+			want:      domain.ClientInfo{OS: domain.ClientOSAndroid, Device: domain.DeviceMobile, Country: "FR"},
+		},
+		{
+			name:      "unknown header value falls back to database",
+			visitor:   domain.Visitor{IP: netip.MustParseAddr("81.2.3.4"), UserAgent: windowsUserAgent, Country: "XX"},
+			rules:     countryRules,
+			countries: geoIP,
+			want:      domain.ClientInfo{OS: domain.ClientOSWindows, Device: domain.DeviceDesktop, Country: "DE"},
+		},
+		{
+			name:    "no database",
+			visitor: domain.Visitor{IP: netip.MustParseAddr("81.2.3.4"), UserAgent: windowsUserAgent},
+			rules:   countryRules,
+			want:    domain.ClientInfo{OS: domain.ClientOSWindows, Device: domain.DeviceDesktop},
+		},
+		{
+			name:    "country isn't looked up without country rules",
+			visitor: domain.Visitor{IP: netip.MustParseAddr("81.2.3.4"), UserAgent: iPhoneUserAgent, Country: "DE"},
+			rules:   deviceRules,
+			countries: countryLookupFunc(func(netip.Addr) string {
+				t.Error("Country() called for rules without countries")
+				return ""
+			}),
+			want: domain.ClientInfo{OS: domain.ClientOSIOS, Device: domain.DeviceMobile},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := visitorClient(&tt.visitor, tt.rules, tt.countries); got != tt.want {
+				t.Errorf("visitorClient() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeCountries(t *testing.T) {
+	got, err := normalizeCountries([]string{"de", " AT ", "DE"})
+	if err != nil {
+		t.Fatalf("normalizeCountries() error = %v", err)
+	}
+	if len(got) != 2 || got[0] != "DE" || got[1] != "AT" {
+		t.Errorf("normalizeCountries() = %v, want [DE AT]", got)
+	}
+
+	for _, code := range []string{"DEU", "D", "1A", "XX", ""} {
+		if _, err := normalizeCountries([]string{code}); err == nil {
+			t.Errorf("normalizeCountries(%q) error = nil", code)
+		}
+	}
+}
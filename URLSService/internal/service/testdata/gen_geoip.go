@@ -0,0 +1,139 @@
+//go:build ignore
+
+// gen_geoip writes tiny IPv4 MaxMind-format country databases used by geoip tests:
+//
+//	go run gen_geoip.go
+package main
+
+import (
+	"encoding/binary"
+	"log"
+	"net/netip"
+	"os"
+)
+
+// network maps prefix to a record, empty country leaves only registered_country set
+type network struct {
+	prefix     netip.Prefix
+	country    string
+	registered string
+}
+
+func main() {
+	write("geoip-country.mmdb", []network{
+		{netip.MustParsePrefix("81.0.0.0/8"), "DE", "DE"},
+		{netip.MustParsePrefix("9.9.0.0/16"), "US", "US"},
+		// Anycast style network without location, only registration country is known
+		{netip.MustParsePrefix("1.1.1.0/24"), "", "AU"},
+	})
+	write("geoip-country-updated.mmdb", []network{
+		{netip.MustParsePrefix("81.0.0.0/8"), "AT", "AT"},
+	})
+}
+
+// node is a search tree node, children are nil for empty subtrees
+type node struct {
+	children [2]*node
+	data     [2]int // data section offset + 1 of a leaf record, 0 when none
+}
+
+func write(name string, networks []network) {
+	root := &node{}
+	var data []byte
+	for _, n := range networks {
+		offset := len(data)
+		data = append(data, record(n)...)
+
+		addr := n.prefix.Addr().As4()
+		current := root
+		for i := 0; i < n.prefix.Bits(); i++ {
+			bit := (addr[i/8] >> (7 - i%8)) & 1
+			if i == n.prefix.Bits()-1 {
+				current.data[bit] = offset + 1
+				break
+			}
+			if current.children[bit] == nil {
+				current.children[bit] = &node{}
+			}
+			current = current.children[bit]
+		}
+	}
+
+	// Breadth first numbering, root must be node 0
+	nodes := []*node{root}
+	index := map[*node]int{root: 0}
+	for i := 0; i < len(nodes); i++ {
+		for _, child := range nodes[i].children {
+			if child != nil {
+				index[child] = len(nodes)
+				nodes = append(nodes, child)
+			}
+		}
+	}
+
+	count := len(nodes)
+	var out []byte
+	for _, n := range nodes {
+		for bit := range 2 {
+			value := count // empty
+			switch {
+			case n.children[bit] != nil:
+				value = index[n.children[bit]]
+			case n.data[bit] != 0:
+				value = count + 16 + n.data[bit] - 1
+			}
+			out = append(out, byte(value>>16), byte(value>>8), byte(value))
+		}
+	}
+	out = append(out, make([]byte, 16)...)
+	out = append(out, data...)
+	out = append(out, "\xAB\xCD\xEFMaxMind.com"...)
+	out = append(out, metadata(count)...)
+
+	if err := os.WriteFile(name, out, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func record(n network) []byte {
+	size := 1
+	if n.country != "" {
+		size = 2
+	}
+	out := []byte{0xE0 | byte(size)}
+	if n.country != "" {
+		out = append(out, str("country")...)
+		out = append(out, isoCode(n.country)...)
+	}
+	out = append(out, str("registered_country")...)
+	return append(out, isoCode(n.registered)...)
+}
+
+func isoCode(code string) []byte {
+	return append(append([]byte{0xE1}, str("iso_code")...), str(code)...)
+}
+
+func metadata(nodeCount int) []byte {
+	out := []byte{0xE7}
+	out = append(out, str("node_count")...)
+	out = append(out, 0xC4)
+	out = binary.BigEndian.AppendUint32(out, uint32(nodeCount))
+	out = append(out, str("record_size")...)
+	out = append(out, 0xA1, 24)
+	out = append(out, str("ip_version")...)
+	out = append(out, 0xA1, 4)
+	out = append(out, str("binary_format_major_version")...)
+	out = append(out, 0xA1, 2)
+	out = append(out, str("binary_format_minor_version")...)
+	out = append(out, 0xA0)
+	out = append(out, str("database_type")...)
+	out = append(out, str("Test-Country")...)
+	out = append(out, str("build_epoch")...)
+	// uint64 is an extended type: size in control byte, type - 7 in the next one
+	out = append(out, 0x04, 0x02)
+	return binary.BigEndian.AppendUint32(out, 1760000000)
+}
+
+func str(s string) []byte {
+	return append([]byte{0x40 | byte(len(s))}, s...)
+}
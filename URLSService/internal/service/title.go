@@ -0,0 +1,27 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxTitleLength matches urls.title column width
+const maxTitleLength = 200
+
+var ErrInvalidTitle = errors.New("invalid title")
+
+// normalizeTitle trims title, it is rendered as plain text so only control characters are rejected
+func normalizeTitle(title string) (string, error) {
+	title = strings.TrimSpace(title)
+	if utf8.RuneCountInString(title) > maxTitleLength {
+		return "", fmt.Errorf("%w: must be at most %d characters", ErrInvalidTitle, maxTitleLength)
+	}
+	if !utf8.ValidString(title) || strings.IndexFunc(title, unicode.IsControl) >= 0 {
+		return "", fmt.Errorf("%w: control characters are not allowed", ErrInvalidTitle)
+	}
+
+	return title, nil
+}
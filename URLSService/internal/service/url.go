@@ -2,119 +2,430 @@ package service
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/hex"
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/cache"
 	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
 	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
-const maxRetries = 3
+const (
+	maxRetries = 3
+
+	// shortCodeIndex is the unique index guarding short codes and aliases
+	shortCodeIndex = "idx_url_short_code"
+)
 
 var (
-	ErrNotFound         = errors.New("URL not found")
-	ErrInvalidURL       = errors.New("invalid URL")
-	ErrInvalidTTL       = errors.New("invalid TTL")
-	ErrInvalidShortCode = errors.New("invalid short code")
-	ErrForbidden        = errors.New("access denied")
+	ErrNotFound            = errors.New("URL not found")
+	ErrExpired             = errors.New("URL expired")
+	ErrInvalidURL          = errors.New("invalid URL")
+	ErrInvalidTTL          = errors.New("invalid TTL")
+	ErrInvalidShortCode    = errors.New("invalid short code")
+	ErrInvalidRedirectType = errors.New("invalid redirect type")
+	ErrInvalidAlias        = errors.New("invalid alias")
+	ErrAliasTaken          = errors.New("alias already taken")
+	ErrForbidden           = errors.New("access denied")
+	ErrDisabled            = errors.New("URL disabled")
+	ErrNothingToUpdate     = errors.New("nothing to update")
+	ErrVersionMismatch     = errors.New("URL was modified")
 )
 
+// validRedirectTypes lists statuses a link may redirect with, 0 falls back to service default
+var validRedirectTypes = map[int]bool{
+	0:                            true,
+	http.StatusMovedPermanently:  true,
+	http.StatusFound:             true,
+	http.StatusTemporaryRedirect: true,
+	http.StatusPermanentRedirect: true,
+}
+
+// ListURLsParams selects a page of user's URLs, zero filters are not applied
+type ListURLsParams struct {
+	UserID        string
+	Host          string
+	Search        string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	ExpiresAfter  time.Time
+	ExpiresBefore time.Time
+	Status        string   // active, expired, disabled or all; not expired links when empty
+	Tags          []string // links carrying all of them
+	Collection    string
+	Trash         bool   // list trashed links instead, Status must be empty
+	Sort          string // created (default) or expires
+	Order         string // desc (default) or asc
+	Limit         int
+	Cursor        string // next_cursor of previous page
+	Offset        int    // Deprecated: used only without Cursor
+	WithTotal     bool
+}
+
+// URLPage is a page of user's URLs, NextCursor is empty on the last page
+type URLPage struct {
+	URLs       []*domain.URL
+	NextCursor string
+	Total      *int // set only when requested
+}
+
+// UpdateURLParams holds fields owner may edit, nil fields are left unchanged
+type UpdateURLParams struct {
+	OriginalURL  *string
+	TTLMinutes   *int // new expiry counted from now
+	Active       *bool
+	Title        *string // "" removes it
+	Interstitial *bool
+	Targets      *[]domain.TargetRule // empty list removes targeting
+	// IfMatch lists versions the change is allowed for, nil skips the check
+	IfMatch []int
+}
+
+// CreateURLParams holds everything needed to create a short URL
+type CreateURLParams struct {
+	OriginalURL  string
+	TTLMinutes   int
+	RedirectType int
+	Alias        string              // optional, generated short code is used when empty
+	Password     string              // optional, only its hash is stored
+	MaxClicks    int                 // optional, 0 means unlimited
+	Tags         []string            // optional, owner's labels
+	Collection   string              // optional, owner's collection
+	Dedupe       bool                // return user's live link to the same destination instead of creating one
+	Title        string              // optional, shown on preview page
+	Interstitial bool                // visitors confirm on a warning page before redirect
+	Targets      []domain.TargetRule // optional, device or country specific destinations
+	UserID       *string
+}
+
 type URLService interface {
-	CreateShortURL(ctx context.Context, originalUrl string, ttlMinutes int, userID *string) (*domain.URL, error)
-	GetURL(ctx context.Context, shortCode string) (*domain.URL, error)
-	GetUserURLs(ctx context.Context, userID string, limit, offset int) ([]*domain.URL, error)
+	// CreateShortURL creates link, existing reports that dedupe returned user's live link instead
+	CreateShortURL(ctx context.Context, params CreateURLParams) (url *domain.URL, existing bool, err error)
+	// CreateShortURLs creates many links at once, results follow items order
+	CreateShortURLs(ctx context.Context, items []CreateURLParams) ([]BatchResult, error)
+	// GetURL returns link for visitor, password is checked only for protected links
+	GetURL(ctx context.Context, shortCode string, password string) (*domain.URL, error)
+	// LookupURL is GetURL without password check, callers must not reveal destination of protected links
+	LookupURL(ctx context.Context, shortCode string) (*domain.URL, error)
+	// ResolveURL is GetURL for visitors following the link, it records a click on success.
+	// Interstitial links need confirmed visit, ConfirmationRequiredError is returned otherwise.
+	// Returned link leads to destination chosen by its targeting rules for the visitor.
+	ResolveURL(ctx context.Context, shortCode string, password string, visitor *domain.Visitor, confirmed bool) (*domain.URL, error)
+	// TargetURL returns copy of link leading to destination its targeting rules choose for visitor
+	TargetURL(ctx context.Context, url *domain.URL, visitor *domain.Visitor) (*domain.URL, error)
+	GetUserURLs(ctx context.Context, params ListURLsParams) (*URLPage, error)
+	// DeleteURL moves owned link to trash, it stops resolving but keeps its short code
 	DeleteURL(ctx context.Context, shortCode string, userID string) error
+	// RestoreURL takes owned link out of trash
+	RestoreURL(ctx context.Context, shortCode string, userID string) (*domain.URL, error)
+	UpdateURL(ctx context.Context, shortCode string, userID string, params UpdateURLParams) (*domain.URL, error)
 }
 
 type urlService struct {
-	repo repository.URLRepository
+	repo      repository.URLRepository
+	clicks    ClickRecorder
+	validator *URLValidator
+	blocklist *Blocklist
+	passwords *LinkPasswords
+	counter   cache.ClickCounter
+	codes     CodeGenerator
+	// foldCase makes lookups retry lowercased code, generated codes must be lowercase only
+	foldCase bool
+	// metadata is nil when destination metadata isn't collected
+	metadata MetadataQueue
+	// countries is nil without geoip database, geo rules then rely on trusted proxy header only
+	countries CountryLookup
 }
 
-func NewURLService(repo repository.URLRepository) URLService {
-	return &urlService{repo: repo}
+func NewURLService(
+	repo repository.URLRepository,
+	clicks ClickRecorder,
+	validator *URLValidator,
+	blocklist *Blocklist,
+	passwords *LinkPasswords,
+	clickCounter cache.ClickCounter,
+	codes CodeGenerator,
+	caseInsensitiveCodes bool,
+	metadata MetadataQueue,
+	countries CountryLookup,
+) URLService {
+	return &urlService{
+		repo:      repo,
+		clicks:    clicks,
+		validator: validator,
+		blocklist: blocklist,
+		passwords: passwords,
+		counter:   clickCounter,
+		codes:     codes,
+		foldCase:  caseInsensitiveCodes,
+		metadata:  metadata,
+		countries: countries,
+	}
 }
 
-func (s *urlService) CreateShortURL(ctx context.Context, originalUrl string, ttlMinutes int, userID *string) (*domain.URL, error) {
-	if originalUrl == "" {
-		return nil, ErrInvalidURL
+func (s *urlService) CreateShortURL(ctx context.Context, params CreateURLParams) (*domain.URL, bool, error) {
+	url, err := s.prepareURL(ctx, params)
+	if err != nil {
+		return nil, false, err
 	}
-	if ttlMinutes <= 0 {
-		return nil, ErrInvalidTTL
+
+	if params.Dedupe {
+		duplicates, err := s.findDuplicates(ctx, *url.UserID, []*domain.URL{url})
+		if err != nil {
+			return nil, false, err
+		}
+		if existing, ok := duplicates[dedupeKey(url)]; ok {
+			return existing, true, nil
+		}
+	}
+
+	if params.Alias != "" {
+		url, err = s.createWithAlias(ctx, url)
+		return url, false, err
 	}
 
 	var lastErr error
 
 	for attempt := 0; attempt < maxRetries; attempt++ {
-		createdAt := time.Now()
-		expiresAt := createdAt.Add(time.Minute * time.Duration(ttlMinutes))
-		shortCode, err := generateShortCode()
+		url.ShortCode, err = s.codes.Generate(ctx)
 		if err != nil {
-			return nil, err
-		}
-
-		url := &domain.URL{
-			ShortCode:   shortCode,
-			OriginalURL: originalUrl,
-			UserID:      userID,
-			ExpiresAt:   expiresAt,
-			CreatedAt:   createdAt,
+			return nil, false, err
 		}
 
 		err = s.repo.Create(ctx, url)
 		if err == nil {
-			return url, nil
+			s.codes.Observe(false)
+			s.queueMetadata(ctx, url)
+			return url, false, nil
 		}
 
-		if isUniqueViolation(err) {
+		if isShortCodeViolation(err) {
+			s.codes.Observe(true)
 			lastErr = err
 			continue
 		}
 
+		return nil, false, err
+	}
+
+	return nil, false, fmt.Errorf("failed to generate short code after %d attempts: %w", maxRetries, lastErr)
+}
+
+// prepareURL validates params and builds URL to insert, short code is left empty unless alias is set
+func (s *urlService) prepareURL(ctx context.Context, params CreateURLParams) (*domain.URL, error) {
+	normalized, err := s.validator.Normalize(params.OriginalURL)
+	if err != nil {
 		return nil, err
 	}
+	params.OriginalURL = normalized
 
-	return nil, fmt.Errorf("failed to generate short code after %d attempts: %w", maxRetries, lastErr)
+	if err := s.blocklist.Check(ctx, normalized); err != nil {
+		return nil, err
+	}
+
+	if params.TTLMinutes <= 0 {
+		return nil, ErrInvalidTTL
+	}
+	if !validRedirectTypes[params.RedirectType] {
+		return nil, ErrInvalidRedirectType
+	}
+	if params.MaxClicks < 0 || params.MaxClicks > maxClicksLimit {
+		return nil, fmt.Errorf("%w: must be between 0 and %d", ErrInvalidMaxClicks, maxClicksLimit)
+	}
+	if params.Alias != "" {
+		if err := validateAlias(params.Alias); err != nil {
+			return nil, err
+		}
+	}
+
+	if params.Title, err = normalizeTitle(params.Title); err != nil {
+		return nil, err
+	}
+	if params.Targets, err = s.prepareTargets(ctx, params.Targets); err != nil {
+		return nil, err
+	}
+
+	if params.Dedupe {
+		if err := validateDedupe(params); err != nil {
+			return nil, err
+		}
+	}
+	if len(params.Tags) > 0 || params.Collection != "" {
+		if params.UserID == nil {
+			return nil, fmt.Errorf("%w: tags and collections are available to signed in users only", ErrInvalidLabel)
+		}
+		if params.Tags, err = normalizeTags(params.Tags); err != nil {
+			return nil, err
+		}
+		if params.Collection, err = normalizeCollection(params.Collection); err != nil {
+			return nil, err
+		}
+	}
+
+	var passwordHash string
+	if params.Password != "" {
+		passwordHash, err = s.passwords.Hash(params.Password)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newURL(params.Alias, params, passwordHash), nil
+}
+
+// createWithAlias inserts link under user chosen code, collisions are reported instead of retried
+func (s *urlService) createWithAlias(ctx context.Context, url *domain.URL) (*domain.URL, error) {
+	if err := s.repo.Create(ctx, url); err != nil {
+		if isShortCodeViolation(err) {
+			return nil, ErrAliasTaken
+		}
+		return nil, err
+	}
+	s.queueMetadata(ctx, url)
+
+	return url, nil
 }
 
-func (s *urlService) GetURL(ctx context.Context, shortCode string) (*domain.URL, error) {
+// queueMetadata schedules fetching details of saved link's destination
+func (s *urlService) queueMetadata(ctx context.Context, url *domain.URL) {
+	if s.metadata != nil {
+		s.metadata.Enqueue(ctx, url)
+	}
+}
+
+func (s *urlService) GetURL(ctx context.Context, shortCode string, password string) (*domain.URL, error) {
+	url, err := s.LookupURL(ctx, shortCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.passwords.Verify(ctx, url, password); err != nil {
+		return nil, err
+	}
+
+	return url, nil
+}
+
+func (s *urlService) LookupURL(ctx context.Context, shortCode string) (*domain.URL, error) {
 	if shortCode == "" {
 		return nil, ErrInvalidShortCode
 	}
 
-	url, err := s.repo.GetByShortCode(ctx, shortCode)
+	url, err := s.getByShortCode(ctx, shortCode)
 	if err != nil {
 		switch {
 		case errors.Is(err, repository.ErrNotFound):
 			return nil, ErrNotFound
+		case errors.Is(err, repository.ErrExpired):
+			return nil, ErrExpired
 		default:
 			return nil, err
 		}
 	}
 
+	// Rules may be added after link creation, so destination is checked on every resolve
+	if url.Disabled {
+		return nil, ErrDisabled
+	}
+
+	if err := s.blocklist.Check(ctx, url.OriginalURL); err != nil {
+		return nil, err
+	}
+
 	return url, nil
 }
 
-func (s *urlService) GetUserURLs(ctx context.Context, userID string, limit, offset int) ([]*domain.URL, error) {
-	if userID == "" {
+// getByShortCode looks code up as is, in case-insensitive mode a miss is retried lowercased,
+// so aliases with capitals still match exactly and generated codes match in any case
+func (s *urlService) getByShortCode(ctx context.Context, shortCode string) (*domain.URL, error) {
+	url, err := s.repo.GetByShortCode(ctx, shortCode)
+	if !s.foldCase || !errors.Is(err, repository.ErrNotFound) {
+		return url, err
+	}
+
+	folded := strings.ToLower(shortCode)
+	if folded == shortCode {
+		return nil, err
+	}
+
+	return s.repo.GetByShortCode(ctx, folded)
+}
+
+func (s *urlService) ResolveURL(ctx context.Context, shortCode string, password string, visitor *domain.Visitor, confirmed bool) (*domain.URL, error) {
+	url, err := s.GetURL(ctx, shortCode, password)
+	if err != nil {
+		return nil, err
+	}
+
+	// Targeted first, so warning page names the site visitor is actually sent to
+	target, err := s.TargetURL(ctx, url, visitor)
+	if err != nil {
+		return nil, err
+	}
+
+	// Warning page is not a click, clicks are spent once visitor continues
+	if url.Interstitial && !confirmed {
+		return nil, &ConfirmationRequiredError{URL: target}
+	}
+
+	if url.MaxClicks > 0 {
+		if err := s.consumeClick(ctx, url); err != nil {
+			return nil, err
+		}
+	}
+
+	s.clicks.Record(ctx, newClick(url.ShortCode, visitor))
+
+	return target, nil
+}
+
+func (s *urlService) GetUserURLs(ctx context.Context, params ListURLsParams) (*URLPage, error) {
+	if params.UserID == "" {
 		return nil, ErrForbidden
 	}
 
-	if limit <= 0 {
-		limit = 20
+	query, err := newURLListQuery(params)
+	if err != nil {
+		return nil, err
 	}
-	if limit > 100 {
-		limit = 100
+
+	if params.Cursor != "" {
+		cursor, err := decodeCursor(params.Cursor, query)
+		if err != nil {
+			return nil, err
+		}
+		query.After = cursor
+	} else if params.Offset > 0 {
+		query.Offset = params.Offset
 	}
-	if offset < 0 {
-		offset = 0
+
+	// One extra row tells whether next page exists without a count query
+	query.Limit++
+	urls, err := s.repo.ListByUserID(ctx, query)
+	if err != nil {
+		return nil, err
 	}
 
-	return s.repo.GetByUserID(ctx, userID, limit, offset)
+	page := &URLPage{URLs: urls}
+	if len(urls) == query.Limit {
+		page.URLs = urls[:len(urls)-1]
+		page.NextCursor = encodeCursor(page.URLs[len(page.URLs)-1], query)
+	}
+
+	if params.WithTotal {
+		total, err := s.repo.CountByUserID(ctx, query.URLFilter)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+
+	return page, nil
 }
 
 func (s *urlService) DeleteURL(ctx context.Context, shortCode string, userID string) error {
@@ -137,13 +448,44 @@ func (s *urlService) DeleteURL(ctx context.Context, shortCode string, userID str
 	return nil
 }
 
-func generateShortCode() (string, error) {
-	bytes := make([]byte, 4)
-	if _, err := rand.Read(bytes); err != nil {
-		return "", fmt.Errorf("failed to generate random bytes %w", err)
+func (s *urlService) RestoreURL(ctx context.Context, shortCode string, userID string) (*domain.URL, error) {
+	if shortCode == "" {
+		return nil, ErrInvalidShortCode
 	}
+	if userID == "" {
+		return nil, ErrForbidden
+	}
+
+	url, err := s.repo.Restore(ctx, shortCode, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return url, nil
+}
+
+func newURL(shortCode string, params CreateURLParams, passwordHash string) *domain.URL {
+	createdAt := time.Now()
 
-	return hex.EncodeToString(bytes), nil
+	return &domain.URL{
+		ShortCode:    shortCode,
+		OriginalURL:  params.OriginalURL,
+		UserID:       params.UserID,
+		RedirectType: params.RedirectType,
+		Version:      1,
+		PasswordHash: passwordHash,
+		MaxClicks:    params.MaxClicks,
+		Tags:         params.Tags,
+		Collection:   params.Collection,
+		Title:        params.Title,
+		Interstitial: params.Interstitial,
+		Targets:      params.Targets,
+		ExpiresAt:    createdAt.Add(time.Minute * time.Duration(params.TTLMinutes)),
+		CreatedAt:    createdAt,
+	}
 }
 
 func isUniqueViolation(err error) bool {
@@ -154,3 +496,14 @@ func isUniqueViolation(err error) bool {
 
 	return false
 }
+
+// isShortCodeViolation reports unique violations caused by an already used short code
+func isShortCodeViolation(err error) bool {
+	if !isUniqueViolation(err) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	errors.As(err, &pgErr)
+	return pgErr.ConstraintName == shortCodeIndex
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+)
+
+const (
+	// MaxBatchSize limits items accepted by one batch create
+	MaxBatchSize = 1000
+	// MaxBatchPasswords limits protected items of one batch, each costs a bcrypt hash
+	// and a thousand of them wouldn't finish before the server write timeout
+	MaxBatchPasswords = 10
+)
+
+var ErrInvalidBatch = errors.New("invalid batch")
+
+// BatchResult is outcome of one batch item, either URL or Err is set.
+// Existing reports that dedupe returned user's live link instead of creating one.
+type BatchResult struct {
+	URL      *domain.URL
+	Err      error
+	Existing bool
+}
+
+func (s *urlService) CreateShortURLs(ctx context.Context, items []CreateURLParams) ([]BatchResult, error) {
+	if len(items) == 0 || len(items) > MaxBatchSize {
+		return nil, fmt.Errorf("%w: must contain 1 to %d items", ErrInvalidBatch, MaxBatchSize)
+	}
+
+	protected := 0
+	for _, params := range items {
+		if params.Password != "" {
+			protected++
+		}
+	}
+	if protected > MaxBatchPasswords {
+		return nil, fmt.Errorf("%w: at most %d items may have password", ErrInvalidBatch, MaxBatchPasswords)
+	}
+
+	results := make([]BatchResult, len(items))
+	urls := make([]*domain.URL, len(items))
+	for i, params := range items {
+		urls[i], results[i].Err = s.prepareURL(ctx, params)
+	}
+
+	if err := s.dedupeBatch(ctx, items, urls, results); err != nil {
+		return nil, err
+	}
+
+	// taken holds codes claimed within the batch, one statement can't tell duplicate rows apart
+	taken := make(map[string]bool, len(items))
+	pending := make([]int, 0, len(items))
+	// firstByKey holds first item creating a deduped destination, later ones share its result
+	firstByKey := make(map[string]int)
+	sameAs := make(map[int]int)
+
+	for i, url := range urls {
+		if url == nil || results[i].URL != nil {
+			continue
+		}
+
+		if items[i].Dedupe {
+			if first, ok := firstByKey[dedupeKey(url)]; ok {
+				sameAs[i] = first
+				continue
+			}
+			firstByKey[dedupeKey(url)] = i
+		}
+
+		if items[i].Alias != "" {
+			if taken[url.ShortCode] {
+				results[i].Err = ErrAliasTaken
+				continue
+			}
+		} else {
+			var err error
+			if url.ShortCode, err = s.generateUniqueShortCode(ctx, taken); err != nil {
+				return nil, err
+			}
+		}
+
+		taken[url.ShortCode] = true
+		pending = append(pending, i)
+	}
+
+	for attempt := 0; len(pending) > 0 && attempt < maxRetries; attempt++ {
+		batch := make([]*domain.URL, 0, len(pending))
+		for _, i := range pending {
+			batch = append(batch, urls[i])
+		}
+
+		inserted, err := s.repo.CreateBatch(ctx, batch)
+		if err != nil {
+			if attempt == 0 {
+				return nil, err
+			}
+			// Earlier attempts are already stored, report only what's left as failed
+			for _, i := range pending {
+				results[i].Err = err
+			}
+			return results, nil
+		}
+
+		// Only generated codes that collided with existing rows are retried
+		retry := pending[:0]
+		for _, i := range pending {
+			switch {
+			case inserted[urls[i].ShortCode]:
+				if items[i].Alias == "" {
+					s.codes.Observe(false)
+				}
+				results[i].URL = urls[i]
+				s.queueMetadata(ctx, urls[i])
+			case items[i].Alias != "":
+				results[i].Err = ErrAliasTaken
+			default:
+				s.codes.Observe(true)
+				if urls[i].ShortCode, err = s.generateUniqueShortCode(ctx, taken); err != nil {
+					return nil, err
+				}
+				taken[urls[i].ShortCode] = true
+				retry = append(retry, i)
+			}
+		}
+		pending = retry
+	}
+
+	for _, i := range pending {
+		results[i].Err = fmt.Errorf("failed to generate short code after %d attempts", maxRetries)
+	}
+
+	for i, first := range sameAs {
+		results[i] = results[first]
+		results[i].Existing = results[first].URL != nil
+	}
+
+	return results, nil
+}
+
+// dedupeBatch fills results of dedupe items whose destination user already has a live link for
+func (s *urlService) dedupeBatch(ctx context.Context, items []CreateURLParams, urls []*domain.URL, results []BatchResult) error {
+	candidates := make([]*domain.URL, 0)
+	for i, url := range urls {
+		if url != nil && items[i].Dedupe {
+			candidates = append(candidates, url)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// Items of one batch share the user
+	duplicates, err := s.findDuplicates(ctx, *candidates[0].UserID, candidates)
+	if err != nil {
+		return err
+	}
+
+	for i, url := range urls {
+		if url == nil || !items[i].Dedupe {
+			continue
+		}
+		if existing, ok := duplicates[dedupeKey(url)]; ok {
+			results[i] = BatchResult{URL: existing, Existing: true}
+		}
+	}
+
+	return nil
+}
+
+// generateUniqueShortCode generates code not yet claimed in taken
+func (s *urlService) generateUniqueShortCode(ctx context.Context, taken map[string]bool) (string, error) {
+	for {
+		shortCode, err := s.codes.Generate(ctx)
+		if err != nil {
+			return "", err
+		}
+		if !taken[shortCode] {
+			return shortCode, nil
+		}
+		s.codes.Observe(true)
+	}
+}
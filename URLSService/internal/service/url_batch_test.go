@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCreateShortURLsLimitsPasswords(t *testing.T) {
+	// Nothing else is wired, the batch must be refused before any item is prepared
+	svc := NewURLService(nil, nil, nil, nil, nil, nil, nil, false, nil, nil)
+
+	items := make([]CreateURLParams, MaxBatchPasswords+1)
+	for i := range items {
+		items[i] = CreateURLParams{OriginalURL: "https://example.com", Password: "secret"}
+	}
+
+	_, err := svc.CreateShortURLs(context.Background(), items)
+	if !errors.Is(err, ErrInvalidBatch) {
+		t.Fatalf("err = %v, want ErrInvalidBatch", err)
+	}
+}
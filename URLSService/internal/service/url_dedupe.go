@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+)
+
+var ErrInvalidDedupe = errors.New("invalid dedupe")
+
+// validateDedupe allows dedupe only for plain links of signed in users,
+// a protected, click-limited, interstitial or targeted link can't stand in for another one.
+// Tags, collection and title are refused too, returned existing link wouldn't carry them.
+func validateDedupe(params CreateURLParams) error {
+	if params.UserID == nil {
+		return fmt.Errorf("%w: available to signed in users only", ErrInvalidDedupe)
+	}
+	if params.Alias != "" || params.Password != "" || params.MaxClicks > 0 || params.Interstitial || len(params.Targets) > 0 {
+		return fmt.Errorf("%w: can't be combined with alias, password, max_clicks, interstitial or targets", ErrInvalidDedupe)
+	}
+	if len(params.Tags) > 0 || params.Collection != "" || params.Title != "" {
+		return fmt.Errorf("%w: can't be combined with tags, collection or title", ErrInvalidDedupe)
+	}
+
+	return nil
+}
+
+// findDuplicates returns user's live plain links by dedupeKey, the longest living one per key.
+// Concurrent creates of the same destination may still both insert, Idempotency-Key covers retries.
+func (s *urlService) findDuplicates(ctx context.Context, userID string, urls []*domain.URL) (map[string]*domain.URL, error) {
+	originalURLs := make([]string, 0, len(urls))
+	for _, url := range urls {
+		originalURLs = append(originalURLs, url.OriginalURL)
+	}
+
+	existing, err := s.repo.FindLiveByOriginalURLs(ctx, userID, originalURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates := make(map[string]*domain.URL, len(existing))
+	for _, url := range existing {
+		if _, ok := duplicates[dedupeKey(url)]; !ok {
+			duplicates[dedupeKey(url)] = url
+		}
+	}
+
+	return duplicates, nil
+}
+
+// dedupeKey tells which links are interchangeable: same normalized destination and redirect
+func dedupeKey(url *domain.URL) string {
+	return strconv.Itoa(url.RedirectType) + " " + url.OriginalURL
+}
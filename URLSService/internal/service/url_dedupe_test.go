@@ -0,0 +1,39 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+)
+
+func TestValidateDedupe(t *testing.T) {
+	userID := "user-1"
+
+	tests := []struct {
+		name    string
+		params  CreateURLParams
+		wantErr bool
+	}{
+		{name: "plain link", params: CreateURLParams{UserID: &userID}},
+		{name: "anonymous", params: CreateURLParams{}, wantErr: true},
+		{name: "alias", params: CreateURLParams{UserID: &userID, Alias: "promo"}, wantErr: true},
+		{name: "password", params: CreateURLParams{UserID: &userID, Password: "secret"}, wantErr: true},
+		{name: "max clicks", params: CreateURLParams{UserID: &userID, MaxClicks: 1}, wantErr: true},
+		{name: "interstitial", params: CreateURLParams{UserID: &userID, Interstitial: true}, wantErr: true},
+		{name: "targets", params: CreateURLParams{UserID: &userID, Targets: []domain.TargetRule{{OS: domain.ClientOSIOS}}}, wantErr: true},
+		{name: "tags", params: CreateURLParams{UserID: &userID, Tags: []string{"promo"}}, wantErr: true},
+		{name: "collection", params: CreateURLParams{UserID: &userID, Collection: "campaign"}, wantErr: true},
+		{name: "title", params: CreateURLParams{UserID: &userID, Title: "Spring sale"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.Dedupe = true
+			err := validateDedupe(tt.params)
+			if tt.wantErr != errors.Is(err, ErrInvalidDedupe) {
+				t.Fatalf("err = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
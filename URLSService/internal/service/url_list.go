@@ -0,0 +1,128 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+	"golang.org/x/net/idna"
+)
+
+const maxSearchLength = 200
+
+var ErrInvalidFilter = errors.New("invalid filter")
+
+var urlStatuses = map[string]repository.URLStatus{
+	"":         repository.URLStatusLive,
+	"active":   repository.URLStatusActive,
+	"expired":  repository.URLStatusExpired,
+	"disabled": repository.URLStatusDisabled,
+	"all":      repository.URLStatusAll,
+}
+
+var urlSortFields = map[string]repository.URLSortField{
+	"":        repository.URLSortCreatedAt,
+	"created": repository.URLSortCreatedAt,
+	"expires": repository.URLSortExpiresAt,
+	"deleted": repository.URLSortDeletedAt,
+}
+
+// newURLListQuery validates list params and converts them to repository query
+func newURLListQuery(params ListURLsParams) (repository.URLListQuery, error) {
+	query := repository.URLListQuery{
+		URLFilter: repository.URLFilter{
+			UserID:        params.UserID,
+			Search:        strings.TrimSpace(params.Search),
+			CreatedAfter:  params.CreatedAfter,
+			CreatedBefore: params.CreatedBefore,
+			ExpiresAfter:  params.ExpiresAfter,
+			ExpiresBefore: params.ExpiresBefore,
+		},
+		Limit: params.Limit,
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = 20
+	}
+	if query.Limit > 100 {
+		query.Limit = 100
+	}
+
+	status, ok := urlStatuses[params.Status]
+	if !ok {
+		return query, fmt.Errorf("%w: status must be one of active, expired, disabled, all", ErrInvalidFilter)
+	}
+	query.Status = status
+
+	sortBy, ok := urlSortFields[params.Sort]
+	if !ok {
+		return query, fmt.Errorf("%w: sort must be created, expires or deleted", ErrInvalidFilter)
+	}
+	query.SortBy = sortBy
+
+	// Trash is listed most recently deleted first unless asked otherwise
+	if params.Trash {
+		if params.Status != "" {
+			return query, fmt.Errorf("%w: status can't be used for trash", ErrInvalidFilter)
+		}
+		query.Status = repository.URLStatusTrashed
+		if params.Sort == "" {
+			query.SortBy = repository.URLSortDeletedAt
+		}
+	} else if sortBy == repository.URLSortDeletedAt {
+		return query, fmt.Errorf("%w: sort by deleted is available for trash only", ErrInvalidFilter)
+	}
+
+	switch params.Order {
+	case "", "desc":
+	case "asc":
+		query.Ascending = true
+	default:
+		return query, fmt.Errorf("%w: order must be asc or desc", ErrInvalidFilter)
+	}
+
+	if len(query.Search) > maxSearchLength {
+		return query, fmt.Errorf("%w: search must be at most %d characters", ErrInvalidFilter, maxSearchLength)
+	}
+
+	// Stored hosts are punycode, so filter is converted the same way as destinations.
+	// Bracketed IPv6 literals are stored as is.
+	host := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(params.Host)), ".")
+	if host != "" && !strings.HasPrefix(host, "[") {
+		ascii, err := idna.Lookup.ToASCII(host)
+		if err != nil {
+			return query, fmt.Errorf("%w: host: %v", ErrInvalidFilter, err)
+		}
+		host = ascii
+	}
+	query.Host = host
+
+	if len(params.Tags) > maxTagsPerURL {
+		return query, fmt.Errorf("%w: at most %d tags can be filtered by", ErrInvalidFilter, maxTagsPerURL)
+	}
+	for _, tag := range params.Tags {
+		tag, err := normalizeLabel(domain.LabelTag, tag)
+		if err != nil {
+			return query, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+		}
+		query.Tags = append(query.Tags, tag)
+	}
+	if params.Collection != "" {
+		collection, err := normalizeLabel(domain.LabelCollection, params.Collection)
+		if err != nil {
+			return query, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+		}
+		query.Collection = collection
+	}
+
+	if !query.CreatedAfter.IsZero() && !query.CreatedBefore.IsZero() && !query.CreatedAfter.Before(query.CreatedBefore) {
+		return query, fmt.Errorf("%w: created_from must be before created_to", ErrInvalidFilter)
+	}
+	if !query.ExpiresAfter.IsZero() && !query.ExpiresBefore.IsZero() && !query.ExpiresAfter.Before(query.ExpiresBefore) {
+		return query, fmt.Errorf("%w: expires_from must be before expires_to", ErrInvalidFilter)
+	}
+
+	return query, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/logger"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+	"go.uber.org/zap"
+)
+
+// URLPurger periodically removes URLs expired longer than retention ago
+// and URLs sitting in trash longer than trashRetention
+type URLPurger struct {
+	repo           repository.URLRepository
+	lock           repository.JobLock
+	interval       time.Duration
+	batchSize      int
+	batchPause     time.Duration
+	retention      time.Duration
+	trashRetention time.Duration
+}
+
+func NewURLPurger(
+	repo repository.URLRepository,
+	lock repository.JobLock,
+	interval time.Duration,
+	batchSize int,
+	batchPause time.Duration,
+	retention time.Duration,
+	trashRetention time.Duration,
+) *URLPurger {
+	return &URLPurger{
+		repo:           repo,
+		lock:           lock,
+		interval:       interval,
+		batchSize:      batchSize,
+		batchPause:     batchPause,
+		retention:      retention,
+		trashRetention: trashRetention,
+	}
+}
+
+// Start purges on every tick until ctx is cancelled, a batch in progress is finished first
+func (p *URLPurger) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	logger.AppLogInfo("URL purger started",
+		zap.Duration("interval", p.interval),
+		zap.Int("batch_size", p.batchSize),
+		zap.Duration("retention", p.retention),
+		zap.Duration("trash_retention", p.trashRetention),
+	)
+
+	// Run immediately on start
+	p.purge(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			p.purge(ctx)
+		case <-ctx.Done():
+			logger.AppLogInfo("URL purger stopped")
+			return
+		}
+	}
+}
+
+func (p *URLPurger) purge(ctx context.Context) {
+	started := time.Now()
+	expired, trashed, batches := 0, 0, 0
+
+	ran, err := p.lock.TryRun(ctx, func(ctx context.Context) error {
+		var err error
+		expired, err = p.purgeBatches(ctx, &batches, func(ctx context.Context) (int, error) {
+			return p.repo.DeleteExpired(ctx, started.Add(-p.retention), p.batchSize)
+		})
+		if err != nil {
+			return err
+		}
+
+		trashed, err = p.purgeBatches(ctx, &batches, func(ctx context.Context) (int, error) {
+			return p.repo.DeleteTrashed(ctx, started.Add(-p.trashRetention), p.batchSize)
+		})
+		return err
+	})
+
+	fields := []zap.Field{
+		zap.Int("expired", expired),
+		zap.Int("trashed", trashed),
+		zap.Int("batches", batches),
+		zap.Duration("took", time.Since(started)),
+	}
+	switch {
+	case err != nil:
+		logger.AppLogError("Failed to purge URLs", append(fields, zap.Error(err))...)
+	case !ran:
+		logger.AppLogDebug("URL purge skipped, another replica holds the lock")
+	case expired+trashed > 0:
+		logger.AppLogInfo("URLs purged", fields...)
+	default:
+		logger.AppLogDebug("No URLs to purge", fields...)
+	}
+}
+
+// purgeBatches repeats deleteBatch until a batch comes out short or ctx is cancelled
+func (p *URLPurger) purgeBatches(ctx context.Context, batches *int, deleteBatch func(ctx context.Context) (int, error)) (int, error) {
+	deleted := 0
+	for ctx.Err() == nil {
+		// Batch is not interrupted by shutdown, query timeout bounds it
+		n, err := deleteBatch(context.WithoutCancel(ctx))
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+		*batches++
+
+		if n < p.batchSize {
+			return deleted, nil
+		}
+
+		select {
+		case <-time.After(p.batchPause):
+		case <-ctx.Done():
+		}
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+)
+
+// fakeURLRepository serves single link, calls of methods it doesn't implement panic
+type fakeURLRepository struct {
+	repository.URLRepository
+	url *domain.URL
+}
+
+func (r *fakeURLRepository) GetByShortCode(ctx context.Context, shortCode string) (*domain.URL, error) {
+	if shortCode != r.url.ShortCode {
+		return nil, repository.ErrNotFound
+	}
+	url := *r.url
+	return &url, nil
+}
+
+type countingClickRecorder struct {
+	recorded int
+}
+
+func (r *countingClickRecorder) Record(ctx context.Context, click *domain.Click) {
+	r.recorded++
+}
+
+func TestResolveURLInterstitialShowsTarget(t *testing.T) {
+	repo := &fakeURLRepository{url: &domain.URL{
+		ShortCode:    "app",
+		OriginalURL:  "https://example.com/download",
+		Interstitial: true,
+		Targets:      []domain.TargetRule{{OS: domain.ClientOSIOS, URL: "https://apps.apple.com/app/id1"}},
+		CreatedAt:    time.Now(),
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}}
+	clicks := &countingClickRecorder{}
+	svc := NewURLService(repo, clicks, nil, NewBlocklist(nil, 0), &LinkPasswords{}, nil, nil, false, nil, nil)
+
+	tests := []struct {
+		name      string
+		userAgent string
+		want      string
+	}{
+		{name: "targeted visitor", userAgent: iPhoneUserAgent, want: "https://apps.apple.com/app/id1"},
+		{name: "fallback visitor", userAgent: "curl/8.0", want: "https://example.com/download"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			visitor := &domain.Visitor{UserAgent: tt.userAgent}
+
+			_, err := svc.ResolveURL(context.Background(), "app", "", visitor, false)
+			var confirmErr *ConfirmationRequiredError
+			if !errors.As(err, &confirmErr) {
+				t.Fatalf("err = %v, want ConfirmationRequiredError", err)
+			}
+			if confirmErr.URL.OriginalURL != tt.want {
+				t.Fatalf("warning page destination = %q, want %q", confirmErr.URL.OriginalURL, tt.want)
+			}
+
+			url, err := svc.ResolveURL(context.Background(), "app", "", visitor, true)
+			if err != nil {
+				t.Fatalf("confirmed resolve: %v", err)
+			}
+			if url.OriginalURL != tt.want {
+				t.Fatalf("redirect destination = %q, want %q", url.OriginalURL, tt.want)
+			}
+		})
+	}
+
+	// Only confirmed visits are clicks
+	if clicks.recorded != len(tests) {
+		t.Fatalf("recorded %d clicks, want %d", clicks.recorded, len(tests))
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/repository"
+)
+
+// VersionMismatchError is returned when IfMatch doesn't hold, it carries current version
+// so client can refetch and retry. It matches ErrVersionMismatch.
+type VersionMismatchError struct {
+	Current int
+}
+
+func (e *VersionMismatchError) Error() string {
+	return ErrVersionMismatch.Error()
+}
+
+func (e *VersionMismatchError) Unwrap() error {
+	return ErrVersionMismatch
+}
+
+func (s *urlService) UpdateURL(ctx context.Context, shortCode string, userID string, params UpdateURLParams) (*domain.URL, error) {
+	if shortCode == "" {
+		return nil, ErrInvalidShortCode
+	}
+	if userID == "" {
+		return nil, ErrForbidden
+	}
+	if params.OriginalURL == nil && params.TTLMinutes == nil && params.Active == nil &&
+		params.Title == nil && params.Interstitial == nil && params.Targets == nil {
+		return nil, ErrNothingToUpdate
+	}
+
+	url, err := s.repo.GetByShortCodeAndUserID(ctx, shortCode, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, ErrNotFound
+		case errors.Is(err, repository.ErrForbidden):
+			return nil, ErrForbidden
+		default:
+			return nil, err
+		}
+	}
+
+	if params.IfMatch != nil && !slices.Contains(params.IfMatch, url.Version) {
+		return nil, &VersionMismatchError{Current: url.Version}
+	}
+	expectedVersion := url.Version
+	previousURL := url.OriginalURL
+
+	// Same rules as on create
+	if params.OriginalURL != nil {
+		normalized, err := s.validator.Normalize(*params.OriginalURL)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.blocklist.Check(ctx, normalized); err != nil {
+			return nil, err
+		}
+		url.OriginalURL = normalized
+	}
+	if params.TTLMinutes != nil {
+		if *params.TTLMinutes <= 0 {
+			return nil, ErrInvalidTTL
+		}
+		url.ExpiresAt = time.Now().Add(time.Minute * time.Duration(*params.TTLMinutes))
+	}
+	if params.Active != nil {
+		url.Disabled = !*params.Active
+	}
+	if params.Title != nil {
+		if url.Title, err = normalizeTitle(*params.Title); err != nil {
+			return nil, err
+		}
+	}
+	if params.Interstitial != nil {
+		url.Interstitial = *params.Interstitial
+	}
+	if params.Targets != nil {
+		if url.Targets, err = s.prepareTargets(ctx, *params.Targets); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.repo.Update(ctx, url, expectedVersion)
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			return nil, ErrVersionMismatch
+		}
+		return nil, err
+	}
+	if updated.OriginalURL != previousURL {
+		s.queueMetadata(ctx, updated)
+	}
+
+	return updated, nil
+}
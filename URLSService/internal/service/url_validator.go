@@ -0,0 +1,165 @@
+package service
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// Reasons a destination URL is rejected, all of them match ErrInvalidURL
+var (
+	ErrURLEmpty       = fmt.Errorf("%w: URL is required", ErrInvalidURL)
+	ErrURLTooLong     = fmt.Errorf("%w: URL is too long", ErrInvalidURL)
+	ErrURLMalformed   = fmt.Errorf("%w: URL can't be parsed", ErrInvalidURL)
+	ErrURLScheme      = fmt.Errorf("%w: only http and https schemes are allowed", ErrInvalidURL)
+	ErrURLCredentials = fmt.Errorf("%w: URL must not contain credentials", ErrInvalidURL)
+	ErrURLHost        = fmt.Errorf("%w: URL host is invalid", ErrInvalidURL)
+	ErrURLPrivateHost = fmt.Errorf("%w: private, loopback and link-local hosts are not allowed", ErrInvalidURL)
+)
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// URLValidatorOptions configures URLValidator
+type URLValidatorOptions struct {
+	MaxLength         int
+	StripFragment     bool
+	AllowPrivateHosts bool
+}
+
+// URLValidator checks destination URLs and brings them to canonical form
+type URLValidator struct {
+	opts URLValidatorOptions
+}
+
+func NewURLValidator(opts URLValidatorOptions) *URLValidator {
+	return &URLValidator{opts: opts}
+}
+
+// Normalize validates raw URL and returns its normalized form:
+// lowercase scheme and punycode host, no default port, optionally no fragment
+func (v *URLValidator) Normalize(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", ErrURLEmpty
+	}
+	if len(raw) > v.opts.MaxLength {
+		return "", ErrURLTooLong
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", ErrURLMalformed
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if _, ok := defaultPorts[u.Scheme]; !ok {
+		return "", ErrURLScheme
+	}
+	if u.Opaque != "" {
+		return "", ErrURLMalformed
+	}
+	if u.User != nil {
+		return "", ErrURLCredentials
+	}
+
+	host, err := v.normalizeHost(u.Hostname())
+	if err != nil {
+		return "", err
+	}
+
+	port := u.Port()
+	if port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return "", ErrURLHost
+		}
+		if port == defaultPorts[u.Scheme] {
+			port = ""
+		}
+	}
+
+	u.Host = host
+	if strings.Contains(host, ":") {
+		u.Host = "[" + host + "]"
+	}
+	if port != "" {
+		u.Host += ":" + port
+	}
+
+	if v.opts.StripFragment {
+		u.Fragment = ""
+		u.RawFragment = ""
+	}
+
+	normalized := u.String()
+	if len(normalized) > v.opts.MaxLength {
+		return "", ErrURLTooLong
+	}
+
+	return normalized, nil
+}
+
+func (v *URLValidator) normalizeHost(host string) (string, error) {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" {
+		return "", ErrURLHost
+	}
+
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if !v.opts.AllowPrivateHosts && !IsPublicIP(ip) {
+			return "", ErrURLPrivateHost
+		}
+		return ip.String(), nil
+	}
+
+	// Browsers read hosts like "2130706433" or "0x7f.1" as IPv4, don't let them sneak past the IP check
+	labels := strings.Split(host, ".")
+	if last := labels[len(labels)-1]; isNumericLabel(last) {
+		return "", ErrURLHost
+	}
+
+	ascii, err := idna.Lookup.ToASCII(host)
+	if err != nil {
+		return "", ErrURLHost
+	}
+
+	if !v.opts.AllowPrivateHosts && (ascii == "localhost" || strings.HasSuffix(ascii, ".localhost")) {
+		return "", ErrURLPrivateHost
+	}
+
+	return ascii, nil
+}
+
+// IsPublicIP reports whether ip is routable on the public internet
+func IsPublicIP(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsValid() &&
+		!ip.IsPrivate() &&
+		!ip.IsLoopback() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
+
+func isNumericLabel(label string) bool {
+	if strings.HasPrefix(label, "0x") {
+		return true
+	}
+	if label == "" {
+		return false
+	}
+	for _, r := range label {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+)
+
+// botMarkers are User-Agent substrings of crawlers, link unfurlers and scripted clients
+var botMarkers = []string{
+	"bot", "crawl", "spider", "slurp", "facebookexternalhit", "embedly", "preview",
+	"curl/", "wget/", "python-requests", "go-http-client", "okhttp", "java/", "headless",
+}
+
+// ParseUserAgent tells OS, device class and bots apart, clients it doesn't recognize are desktop.
+// Modern iPads send the desktop Safari User-Agent and are seen as macOS.
+func ParseUserAgent(userAgent string) domain.ClientInfo {
+	ua := strings.ToLower(userAgent)
+	client := domain.ClientInfo{Device: domain.DeviceDesktop}
+
+	if ua == "" {
+		client.Bot = true
+		return client
+	}
+	for _, marker := range botMarkers {
+		if strings.Contains(ua, marker) {
+			client.Bot = true
+			break
+		}
+	}
+
+	switch {
+	case strings.Contains(ua, "windows phone"):
+		client.OS, client.Device = domain.ClientOSWindows, domain.DeviceMobile
+	case strings.Contains(ua, "ipad"):
+		client.OS, client.Device = domain.ClientOSIOS, domain.DeviceTablet
+	case strings.Contains(ua, "iphone"), strings.Contains(ua, "ipod"):
+		client.OS, client.Device = domain.ClientOSIOS, domain.DeviceMobile
+	case strings.Contains(ua, "android"):
+		// Android tablets leave "Mobile" out
+		client.OS, client.Device = domain.ClientOSAndroid, domain.DeviceTablet
+		if strings.Contains(ua, "mobile") {
+			client.Device = domain.DeviceMobile
+		}
+	case strings.Contains(ua, "kindle"), strings.Contains(ua, "silk/"):
+		client.OS, client.Device = domain.ClientOSAndroid, domain.DeviceTablet
+	case strings.Contains(ua, "cros"):
+		client.OS = domain.ClientOSChromeOS
+	case strings.Contains(ua, "windows"):
+		client.OS = domain.ClientOSWindows
+	case strings.Contains(ua, "macintosh"), strings.Contains(ua, "mac os x"):
+		client.OS = domain.ClientOSMacOS
+	case strings.Contains(ua, "linux"):
+		client.OS = domain.ClientOSLinux
+	case strings.Contains(ua, "mobile"):
+		client.Device = domain.DeviceMobile
+	}
+
+	return client
+}
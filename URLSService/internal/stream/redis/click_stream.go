@@ -0,0 +1,227 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/stream"
+	"github.com/redis/go-redis/v9"
+)
+
+// Compact field names of a click entry
+const (
+	fieldShortCode = "c"
+	fieldClickedAt = "t" // unix milliseconds
+	fieldReferrer  = "r"
+	fieldUserAgent = "a"
+	fieldIP        = "i"
+	fieldUserID    = "u"
+)
+
+var errMalformedEntry = errors.New("malformed click entry")
+
+type clickStream struct {
+	client   *redis.Client
+	name     string
+	group    string
+	consumer string
+	maxLen   int64
+}
+
+func NewClickStream(client *redis.Client, name, group, consumer string, maxLen int64) stream.ClickStream {
+	return &clickStream{
+		client:   client,
+		name:     name,
+		group:    group,
+		consumer: consumer,
+		maxLen:   maxLen,
+	}
+}
+
+func (s *clickStream) Publish(ctx context.Context, clicks []*domain.Click) error {
+	if len(clicks) == 0 {
+		return nil
+	}
+
+	pipe := s.client.Pipeline()
+	for _, click := range clicks {
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: s.name,
+			MaxLen: s.maxLen,
+			Approx: true,
+			Values: encodeClick(click),
+		})
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis pipeline error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *clickStream) EnsureGroup(ctx context.Context) error {
+	// Start from the beginning so entries published before group existed are not skipped
+	err := s.client.XGroupCreateMkStream(ctx, s.name, s.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("redis xgroup create error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *clickStream) ReadPending(ctx context.Context, count int64) ([]stream.ClickMessage, error) {
+	return s.read(ctx, "0", count, -1)
+}
+
+func (s *clickStream) ReadNew(ctx context.Context, count int64, block time.Duration) ([]stream.ClickMessage, error) {
+	return s.read(ctx, ">", count, block)
+}
+
+func (s *clickStream) read(ctx context.Context, id string, count int64, block time.Duration) ([]stream.ClickMessage, error) {
+	res, err := s.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    s.group,
+		Consumer: s.consumer,
+		Streams:  []string{s.name, id},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("redis xreadgroup error: %w", err)
+	}
+
+	var messages []stream.ClickMessage
+	for _, str := range res {
+		messages = append(messages, decodeMessages(str.Messages)...)
+	}
+
+	return messages, nil
+}
+
+func (s *clickStream) ClaimIdle(ctx context.Context, minIdle time.Duration, count int64) ([]stream.ClickMessage, error) {
+	res, _, err := s.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   s.name,
+		Group:    s.group,
+		Consumer: s.consumer,
+		MinIdle:  minIdle,
+		Start:    "0-0",
+		Count:    count,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis xautoclaim error: %w", err)
+	}
+
+	return decodeMessages(res), nil
+}
+
+func (s *clickStream) Ack(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := s.client.XAck(ctx, s.name, s.group, ids...).Err(); err != nil {
+		return fmt.Errorf("redis xack error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *clickStream) RemoveIdleConsumers(ctx context.Context, idle time.Duration) (int, error) {
+	consumers, err := s.client.XInfoConsumers(ctx, s.name, s.group).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis xinfo consumers error: %w", err)
+	}
+
+	removed := 0
+	for _, consumer := range consumers {
+		if consumer.Name == s.consumer || consumer.Pending > 0 || consumer.Idle < idle {
+			continue
+		}
+		if err = s.client.XGroupDelConsumer(ctx, s.name, s.group, consumer.Name).Err(); err != nil {
+			return removed, fmt.Errorf("redis xgroup delconsumer error: %w", err)
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+func (s *clickStream) Lag(ctx context.Context) (*stream.GroupLag, error) {
+	groups, err := s.client.XInfoGroups(ctx, s.name).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis xinfo groups error: %w", err)
+	}
+
+	for _, group := range groups {
+		if group.Name == s.group {
+			return &stream.GroupLag{Lag: group.Lag, Pending: group.Pending}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("consumer group %q not found", s.group)
+}
+
+func encodeClick(click *domain.Click) map[string]interface{} {
+	values := map[string]interface{}{
+		fieldShortCode: click.ShortCode,
+		fieldClickedAt: click.ClickedAt.UnixMilli(),
+	}
+	if click.Referrer != "" {
+		values[fieldReferrer] = click.Referrer
+	}
+	if click.UserAgent != "" {
+		values[fieldUserAgent] = click.UserAgent
+	}
+	if click.IP.IsValid() {
+		values[fieldIP] = click.IP.String()
+	}
+	if click.UserID != nil {
+		values[fieldUserID] = *click.UserID
+	}
+
+	return values
+}
+
+func decodeMessages(entries []redis.XMessage) []stream.ClickMessage {
+	messages := make([]stream.ClickMessage, 0, len(entries))
+	for _, entry := range entries {
+		// Undecodable entries are still returned so consumer can acknowledge and drop them
+		click, _ := decodeClick(entry.Values)
+		messages = append(messages, stream.ClickMessage{ID: entry.ID, Click: click})
+	}
+
+	return messages
+}
+
+func decodeClick(values map[string]interface{}) (*domain.Click, error) {
+	shortCode, _ := values[fieldShortCode].(string)
+	clickedAtStr, _ := values[fieldClickedAt].(string)
+	clickedAt, err := strconv.ParseInt(clickedAtStr, 10, 64)
+	if shortCode == "" || err != nil {
+		return nil, errMalformedEntry
+	}
+
+	click := &domain.Click{
+		ShortCode: shortCode,
+		ClickedAt: time.UnixMilli(clickedAt),
+	}
+	click.Referrer, _ = values[fieldReferrer].(string)
+	click.UserAgent, _ = values[fieldUserAgent].(string)
+	if ip, ok := values[fieldIP].(string); ok {
+		click.IP, _ = netip.ParseAddr(ip)
+	}
+	if userID, ok := values[fieldUserID].(string); ok {
+		click.UserID = &userID
+	}
+
+	return click, nil
+}
@@ -0,0 +1,34 @@
+package stream
+
+import (
+	"context"
+	"time"
+
+	"github.com/ArtemBorodinEvgenyevich/URLSService/internal/domain"
+)
+
+// ClickMessage is a click read from the stream, Click is nil if the entry couldn't be decoded
+type ClickMessage struct {
+	ID    string
+	Click *domain.Click
+}
+
+// GroupLag describes how far consumer group is behind the stream
+type GroupLag struct {
+	Lag     int64 // entries not yet delivered to the group, -1 if unknown
+	Pending int64 // entries delivered but not acknowledged
+}
+
+type ClickStream interface {
+	Publish(ctx context.Context, clicks []*domain.Click) error
+	EnsureGroup(ctx context.Context) error
+	// ReadPending returns entries already delivered to this consumer but never acknowledged
+	ReadPending(ctx context.Context, count int64) ([]ClickMessage, error)
+	ReadNew(ctx context.Context, count int64, block time.Duration) ([]ClickMessage, error)
+	// ClaimIdle takes over entries other consumers haven't acknowledged for minIdle
+	ClaimIdle(ctx context.Context, minIdle time.Duration, count int64) ([]ClickMessage, error)
+	Ack(ctx context.Context, ids ...string) error
+	// RemoveIdleConsumers forgets consumers without pending entries that were idle longer than idle
+	RemoveIdleConsumers(ctx context.Context, idle time.Duration) (int, error)
+	Lag(ctx context.Context) (*GroupLag, error)
+}